@@ -0,0 +1,43 @@
+package supervisordkratos
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yyle88/must"
+)
+
+// cpuListPattern taskset cpu list, e.g. "0", "2-3", "0,4-7", "0-10:2"
+// cpuListPattern taskset 的 CPU 列表，例如 "0"、"2-3"、"0,4-7"、"0-10:2"
+var cpuListPattern = regexp.MustCompile(`^\d+(-\d+(:\d+)?)?(,\d+(-\d+(:\d+)?)?)*$`)
+
+// WithCPUAffinity pin command to cpus with `taskset -c`, keeping latency-critical services off batch cores
+// Accepts taskset cpu lists like "2-3" or "0,4-7", ranges must not run backwards, Linux targets only
+//
+// WithCPUAffinity 使用 `taskset -c` 将命令绑定到指定 CPU，使延迟敏感服务远离批处理核心
+// 接受 "2-3" 或 "0,4-7" 形式的 taskset CPU 列表，范围不得倒序，仅支持 Linux 目标
+func (p *ProgramConfig) WithCPUAffinity(cpus string) *ProgramConfig {
+	must.True(isCPUList(cpus))
+	p.CPUAffinity.Set(cpus)
+	return p
+}
+
+// isCPUList checks cpu list syntax and that each range starts at or below its end
+// isCPUList 检查 CPU 列表语法，以及每个范围的起点不大于终点
+func isCPUList(cpus string) bool {
+	if !cpuListPattern.MatchString(cpus) {
+		return false
+	}
+	for _, item := range strings.Split(cpus, ",") {
+		span, _, _ := strings.Cut(item, ":")
+		if lo, hi, ok := strings.Cut(span, "-"); ok {
+			low, _ := strconv.Atoi(lo)
+			high, _ := strconv.Atoi(hi)
+			if low > high {
+				return false
+			}
+		}
+	}
+	return true
+}
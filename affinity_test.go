@@ -0,0 +1,52 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCPUAffinity(t *testing.T) {
+	// Test taskset wraps the command outside sandbox
+	// 测试 taskset 在沙箱之外包装命令
+	program := supervisordkratos.NewProgramConfig(
+		"gateway",
+		"/opt/gateway",
+		"deploy",
+		"/var/log/gateway",
+	).WithCPUAffinity("0,2-3").
+		WithSandbox(supervisordkratos.NewSandbox(supervisordkratos.SandboxUnshare).WithNoNewPrivileges(false))
+
+	content, err := supervisordkratos.BuildProgramConfig(program)
+	require.NoError(t, err)
+	t.Log(content)
+
+	const expected = `[program:gateway]
+user            = deploy
+directory       = /opt/gateway
+command         = taskset -c 0,2-3 unshare --user --map-root-user --mount --pid --ipc --uts --fork --kill-child -- /opt/gateway/bin/gateway
+stdout_logfile  = /var/log/gateway/gateway.log
+stderr_logfile  = /var/log/gateway/gateway.err
+`
+
+	require.Equal(t, expected, content)
+}
+
+func TestWithCPUAffinityInvalid(t *testing.T) {
+	// Test malformed cpu lists and non-Linux targets are refused
+	// 测试格式错误的 CPU 列表和非 Linux 目标被拒绝
+	for _, cpus := range []string{"", "a", "1-", "3-1", "0,,1", " 1"} {
+		require.Panics(t, func() {
+			supervisordkratos.NewProgramConfig("gateway", "/opt/gateway", "deploy", "/var/log/gateway").WithCPUAffinity(cpus)
+		}, cpus)
+	}
+
+	program := supervisordkratos.NewProgramConfig(
+		"gateway",
+		`C:\gateway`,
+		"deploy",
+		`C:\logs`,
+	).WithTargetOS(supervisordkratos.TargetWindows).WithCPUAffinity("1")
+	require.ErrorContains(t, supervisordkratos.ValidateProgramConfig(program), "taskset")
+}
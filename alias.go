@@ -0,0 +1,24 @@
+package supervisordkratos
+
+import (
+	"github.com/yyle88/must"
+)
+
+// WithAlias set short group name wrapping this program alone, e.g. "api"
+// Operators then type "supervisorctl restart api:" instead of the full generated name
+// supervisord starts each group member separately, so aliased programs must not join a GroupConfig
+//
+// WithAlias 设置只包含该程序的短组名，例如 "api"
+// 运维人员可以输入 "supervisorctl restart api:" 而不是完整的生成名称
+// supervisord 会为每个组分别启动成员，因此带别名的程序不能加入 GroupConfig
+func (p *ProgramConfig) WithAlias(alias string) *ProgramConfig {
+	p.Alias.Set(must.Nice(alias))
+	return p
+}
+
+// NewAliasDocument build Document with [group:alias] section then the program section
+// NewAliasDocument 构建包含 [group:alias] 段落和程序段落的 Document
+func NewAliasDocument(program *ProgramConfig) *Document {
+	must.True(program.Alias.IsSet())
+	return NewGroupDocument(NewGroupConfig(program.Alias.Get()).AddProgram(program))
+}
@@ -0,0 +1,41 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgramConfigWithAlias(t *testing.T) {
+	// Test alias renders one-member group ahead of program section
+	// 测试别名在程序段落之前渲染只有一个成员的组
+	program := supervisordkratos.NewProgramConfig(
+		"payments-api-gateway",
+		"/opt/payments-api-gateway",
+		"deploy",
+		"/var/log/payments",
+	).WithAlias("api")
+
+	content := supervisordkratos.GenerateProgramConfig(program)
+	t.Log(content)
+
+	const expected = `[group:api]
+programs=payments-api-gateway
+
+
+[program:payments-api-gateway]
+user            = deploy
+directory       = /opt/payments-api-gateway
+command         = /opt/payments-api-gateway/bin/payments-api-gateway
+stdout_logfile  = /var/log/payments/payments-api-gateway.log
+stderr_logfile  = /var/log/payments/payments-api-gateway.err
+`
+	require.Equal(t, expected, content)
+	require.NoError(t, supervisordkratos.Simulate(content))
+
+	// Aliased programs cannot also join a group
+	// 带别名的程序不能再加入组
+	group := supervisordkratos.NewGroupConfig("payments").AddProgram(program)
+	require.Error(t, supervisordkratos.ValidateGroupConfig(group))
+}
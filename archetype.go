@@ -0,0 +1,101 @@
+package supervisordkratos
+
+import (
+	"github.com/yyle88/must/mustslice"
+)
+
+// Archetype curated option set of a common service shape
+// Archetype 常见服务形态的预设选项集合
+type Archetype string
+
+const (
+	ArchetypeAPIGateway    Archetype = "api-gateway"    // HTTP edge, restarts always, drains connections // HTTP 边缘服务，总是重启，排空连接
+	ArchetypeGRPCService   Archetype = "grpc-service"   // Kratos business service, restarts always // Kratos 业务服务，总是重启
+	ArchetypeCronWorker    Archetype = "cron-worker"    // Long-running scheduler loop // 长期运行的调度循环
+	ArchetypeQueueConsumer Archetype = "queue-consumer" // Consumer draining in-flight messages on stop // 停止时排空处理中消息的消费者
+	ArchetypeBatchJob      Archetype = "batch-job"      // Run-to-completion job started on demand // 按需启动、运行至完成的任务
+)
+
+// archetypeOptions apply curated options of each archetype
+// archetypeOptions 应用每种形态的预设选项
+var archetypeOptions = map[Archetype]func(p *ProgramConfig){
+	ArchetypeAPIGateway: func(p *ProgramConfig) {
+		p.WithPriorityBand(PriorityGateway, 0).
+			WithAutoRestart(true).
+			WithStartSecs(KratosBootstrapSecs).
+			WithStopWaitSecs(30).
+			WithStopAsGroup(true).
+			WithKillAsGroup(true)
+	},
+	ArchetypeGRPCService: func(p *ProgramConfig) {
+		p.WithPriorityBand(PriorityService, 0).
+			WithAutoRestart(true).
+			WithStartSecs(KratosBootstrapSecs).
+			WithStopWaitSecs(30).
+			WithStopAsGroup(true).
+			WithKillAsGroup(true)
+	},
+	ArchetypeCronWorker: func(p *ProgramConfig) {
+		p.WithPriorityBand(PriorityWorker, 0).
+			WithAutoRestart(true).
+			WithStopWaitSecs(60).
+			WithStopAsGroup(true).
+			WithKillAsGroup(true).
+			WithRedirectStderr(true)
+	},
+	ArchetypeQueueConsumer: func(p *ProgramConfig) {
+		p.WithPriorityBand(PriorityWorker, 0).
+			WithAutoRestart(true).
+			WithStopWaitSecs(60).
+			WithStopAsGroup(true).
+			WithKillAsGroup(true)
+	},
+	ArchetypeBatchJob: func(p *ProgramConfig) {
+		p.WithPriorityBand(PriorityWorker, 0).
+			WithAutoStart(false).
+			WithAutoRestart(false).
+			WithStartSecs(0).
+			WithExitCodes([]int{0}).
+			WithStopAsGroup(true).
+			WithKillAsGroup(true).
+			WithRedirectStderr(true)
+	},
+}
+
+// WithArchetype apply curated options of archetype, later With* calls still override them
+// WithArchetype 应用形态的预设选项，之后的 With* 调用仍可覆盖
+func (p *ProgramConfig) WithArchetype(archetype Archetype) *ProgramConfig {
+	mustslice.In(archetype, []Archetype{ArchetypeAPIGateway, ArchetypeGRPCService, ArchetypeCronWorker, ArchetypeQueueConsumer, ArchetypeBatchJob})
+	archetypeOptions[archetype](p)
+	p.Archetype.Set(archetype)
+	return p
+}
+
+// NewFromArchetype create ProgramConfig with curated options of archetype
+// NewFromArchetype 创建带有形态预设选项的 ProgramConfig
+func NewFromArchetype(name string, archetype Archetype, root string, userName string, slogRoot string) *ProgramConfig {
+	return NewProgramConfig(name, root, userName, slogRoot).WithArchetype(archetype)
+}
+
+// NewOneShotProgram create run-to-completion job: autostart=false, autorestart=false,
+// startsecs=0 so short runs count as started, exit code 0 expected
+// Trigger runs with OneShotCommands, EXITED jobs can be started again
+//
+// NewOneShotProgram 创建运行至完成的任务：autostart=false、autorestart=false、
+// startsecs=0 使短时间运行也算启动成功，预期退出码为 0
+// 使用 OneShotCommands 触发运行，处于 EXITED 的任务可以再次启动
+func NewOneShotProgram(name string, root string, userName string, slogRoot string) *ProgramConfig {
+	return NewFromArchetype(name, ArchetypeBatchJob, root, userName, slogRoot)
+}
+
+// OneShotCommands supervisorctl commands triggering one run of job on demand
+// OneShotCommands 按需触发任务运行一次的 supervisorctl 命令
+func OneShotCommands(supervisorctl string, job *ProgramConfig) []string {
+	target := job.Name
+	if job.NumProcs.Get() > 1 {
+		target += ":*"
+	}
+	return []string{
+		supervisorctl + " start " + target,
+	}
+}
@@ -0,0 +1,82 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFromArchetype(t *testing.T) {
+	// Test gRPC service archetype renders curated options
+	// 测试 gRPC 服务形态渲染预设选项
+	program := supervisordkratos.NewFromArchetype("orders", supervisordkratos.ArchetypeGRPCService, "/opt/orders", "deploy", "/var/log/orders")
+
+	content := supervisordkratos.GenerateProgramConfig(program)
+	t.Log(content)
+
+	const expected = `[program:orders]
+user            = deploy
+directory       = /opt/orders
+command         = /opt/orders/bin/orders
+autorestart     = true
+startsecs       = 3
+stdout_logfile  = /var/log/orders/orders.log
+stderr_logfile  = /var/log/orders/orders.err
+stopasgroup     = true
+stopwaitsecs    = 30
+killasgroup     = true
+priority        = 500
+`
+	require.Equal(t, expected, content)
+}
+
+func TestArchetypeCatalog(t *testing.T) {
+	// Test every archetype validates and batch jobs stay manual
+	// 测试每种形态都能通过校验且批处理任务保持手动启动
+	for _, archetype := range []supervisordkratos.Archetype{
+		supervisordkratos.ArchetypeAPIGateway,
+		supervisordkratos.ArchetypeGRPCService,
+		supervisordkratos.ArchetypeCronWorker,
+		supervisordkratos.ArchetypeQueueConsumer,
+		supervisordkratos.ArchetypeBatchJob,
+	} {
+		program := supervisordkratos.NewFromArchetype("svc", archetype, "/opt/svc", "deploy", "/var/log/svc")
+		require.NoError(t, supervisordkratos.ValidateProgramConfig(program), archetype)
+	}
+
+	job := supervisordkratos.NewFromArchetype("report", supervisordkratos.ArchetypeBatchJob, "/opt/report", "deploy", "/var/log/report")
+	require.False(t, job.AutoStart.Get())
+	require.Equal(t, false, job.AutoRestart.Get())
+
+	require.Panics(t, func() {
+		supervisordkratos.NewFromArchetype("svc", "unknown", "/opt/svc", "deploy", "/var/log/svc")
+	})
+}
+
+func TestNewOneShotProgram(t *testing.T) {
+	// Test one-shot job renders run-to-completion options
+	// 测试一次性任务渲染运行至完成的选项
+	job := supervisordkratos.NewOneShotProgram("migrate", "/opt/migrate", "deploy", "/var/log/migrate")
+
+	content := supervisordkratos.GenerateProgramConfig(job)
+	t.Log(content)
+
+	const expected = `[program:migrate]
+user            = deploy
+directory       = /opt/migrate
+command         = /opt/migrate/bin/migrate
+autostart       = false
+autorestart     = false
+startsecs       = 0
+stdout_logfile  = /var/log/migrate/migrate.log
+stderr_logfile  = /var/log/migrate/migrate.err
+redirect_stderr = true
+stopasgroup     = true
+killasgroup     = true
+priority        = 800
+exitcodes       = 0
+`
+	require.Equal(t, expected, content)
+	require.Equal(t, []string{"supervisorctl start migrate"}, supervisordkratos.OneShotCommands("supervisorctl", job))
+}
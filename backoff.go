@@ -0,0 +1,79 @@
+package supervisordkratos
+
+import (
+	"math"
+	"time"
+
+	"github.com/yyle88/must"
+)
+
+// RetryAdviceInput describes tolerated downtime and observed crash pattern
+// RetryAdviceInput 描述可容忍的停机时间和观察到的崩溃模式
+type RetryAdviceInput struct {
+	MaxDowntime   time.Duration // Max time spent retrying before FATAL // 进入 FATAL 前最长重试时间
+	BootstrapTime time.Duration // Time a healthy start needs to be considered up // 健康启动被视为成功所需的时间
+	CrashAfter    time.Duration // Time a failing start runs before it crashes // 失败启动在崩溃前运行的时间
+	RestartAlways bool          // Restart on clean exit codes too // 在正常退出码时也重启
+}
+
+// RetryAdvice recommended supervisord retry settings
+// RetryAdvice 推荐的 supervisord 重试设置
+type RetryAdvice struct {
+	StartRetries int           // Recommended startretries // 推荐的 startretries
+	StartSecs    int           // Recommended startsecs // 推荐的 startsecs
+	AutoRestart  string        // Recommended autorestart mode // 推荐的 autorestart 模式
+	TimeToFatal  time.Duration // Time from first failed start to FATAL // 从首次启动失败到 FATAL 的时间
+}
+
+// AdviseRetry computes startretries/startsecs/autorestart from downtime budget
+// supervisord waits 1s, 2s, ... N seconds between attempts and gives up after startretries+1 failures
+//
+// AdviseRetry 根据停机预算计算 startretries/startsecs/autorestart
+// supervisord 在尝试之间等待 1s、2s ... N 秒，并在 startretries+1 次失败后放弃
+func AdviseRetry(input *RetryAdviceInput) *RetryAdvice {
+	must.Full(input)
+
+	// startsecs must cover bootstrap, otherwise healthy starts flap into BACKOFF
+	// startsecs 必须覆盖启动时间，否则健康启动会在 BACKOFF 中反复
+	startSecs := max(1, int(math.Ceil(input.BootstrapTime.Seconds())))
+
+	// A crash after startsecs counts as EXITED, not a failed start
+	// 在 startsecs 之后崩溃算作 EXITED，而不是启动失败
+	crashAfter := min(input.CrashAfter, time.Duration(startSecs)*time.Second)
+
+	startRetries := 0
+	for TimeToFatal(startRetries+1, crashAfter) <= input.MaxDowntime {
+		startRetries++
+	}
+
+	autoRestart := "unexpected"
+	if input.RestartAlways {
+		autoRestart = "true"
+	}
+	return &RetryAdvice{
+		StartRetries: startRetries,
+		StartSecs:    startSecs,
+		AutoRestart:  autoRestart,
+		TimeToFatal:  TimeToFatal(startRetries, crashAfter),
+	}
+}
+
+// Apply set advised retry settings into program config
+// Apply 将建议的重试设置写入程序配置
+func (a *RetryAdvice) Apply(program *ProgramConfig) *ProgramConfig {
+	return program.
+		WithStartRetries(a.StartRetries).
+		WithStartSecs(a.StartSecs).
+		WithAutoRestartMode(a.AutoRestart)
+}
+
+// TimeToFatal computes time from first start to FATAL when every start crashes after crashAfter
+// Sum of startretries+1 attempts plus BACKOFF delays 1+2+...+startretries seconds
+//
+// TimeToFatal 计算每次启动都在 crashAfter 后崩溃时从首次启动到 FATAL 的时间
+// 等于 startretries+1 次尝试加上 BACKOFF 延迟 1+2+...+startretries 秒
+func TimeToFatal(startRetries int, crashAfter time.Duration) time.Duration {
+	attempts := time.Duration(startRetries + 1)
+	delays := time.Duration(startRetries*(startRetries+1)/2) * time.Second
+	return attempts*crashAfter + delays
+}
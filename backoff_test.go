@@ -0,0 +1,41 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeToFatal(t *testing.T) {
+	// Test default startretries=3 with instant crashes: delays 1+2+3 seconds
+	// 测试默认 startretries=3 且立即崩溃：延迟 1+2+3 秒
+	require.Equal(t, 6*time.Second, supervisordkratos.TimeToFatal(3, 0))
+	require.Equal(t, 10*time.Second, supervisordkratos.TimeToFatal(3, time.Second))
+	require.Equal(t, 2*time.Second, supervisordkratos.TimeToFatal(0, 2*time.Second))
+}
+
+func TestAdviseRetry(t *testing.T) {
+	// Test advice fits within downtime budget and applies to program
+	// 测试建议符合停机预算并可应用到程序
+	advice := supervisordkratos.AdviseRetry(&supervisordkratos.RetryAdviceInput{
+		MaxDowntime:   time.Minute,
+		BootstrapTime: 4500 * time.Millisecond,
+		CrashAfter:    2 * time.Second,
+	})
+	require.Equal(t, 5, advice.StartSecs)
+	require.Equal(t, "unexpected", advice.AutoRestart)
+	require.LessOrEqual(t, advice.TimeToFatal, time.Minute)
+	require.Greater(t, supervisordkratos.TimeToFatal(advice.StartRetries+1, 2*time.Second), time.Minute)
+
+	program := advice.Apply(supervisordkratos.NewProgramConfig(
+		"api",
+		"/opt/api",
+		"deploy",
+		"/var/log/api",
+	))
+	require.Equal(t, advice.StartRetries, program.StartRetries.Get())
+	require.Equal(t, 5, program.StartSecs.Get())
+	require.Equal(t, "unexpected", program.AutoRestart.Get())
+}
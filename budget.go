@@ -0,0 +1,55 @@
+package supervisordkratos
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/yyle88/must"
+)
+
+// ErrRestartBudgetExceeded returned when a program used up its automated restarts in the window
+// ErrRestartBudgetExceeded 当程序在时间窗口内用完自动重启次数时返回
+var ErrRestartBudgetExceeded = errors.New("restart budget exceeded")
+
+// RestartBudget limits automated restarts per program within a sliding time window
+// Guards reconcile loops from thrash-restarting a crash-looping service, not goroutine-safe
+//
+// RestartBudget 在滑动时间窗口内限制每个程序的自动重启次数
+// 防止协调循环反复重启崩溃循环的服务，非并发安全
+type RestartBudget struct {
+	MaxRestarts int                    // Restarts allowed per window // 每个窗口允许的重启次数
+	Window      time.Duration          // Sliding window length // 滑动窗口长度
+	history     map[string][]time.Time // Restart times by program name // 按程序名称记录的重启时间
+}
+
+// NewRestartBudget create RestartBudget allowing maxRestarts per window
+// 创建每个窗口允许 maxRestarts 次重启的 RestartBudget
+func NewRestartBudget(maxRestarts int, window time.Duration) *RestartBudget {
+	must.True(maxRestarts > 0)
+	must.True(window > 0)
+	return &RestartBudget{
+		MaxRestarts: maxRestarts,
+		Window:      window,
+		history:     make(map[string][]time.Time),
+	}
+}
+
+// Allow records a restart of program at now, or returns ErrRestartBudgetExceeded
+// Refused restarts are not recorded, callers should alert instead of restarting
+//
+// Allow 记录程序在 now 时刻的一次重启，或返回 ErrRestartBudgetExceeded
+// 被拒绝的重启不会被记录，调用方应发出告警而不是重启
+func (b *RestartBudget) Allow(program string, now time.Time) error {
+	recent := b.history[program][:0]
+	for _, at := range b.history[program] {
+		if now.Sub(at) < b.Window {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent) >= b.MaxRestarts {
+		b.history[program] = recent
+		return errors.WithMessagef(ErrRestartBudgetExceeded, "program %s: %d restarts within %s", program, len(recent), b.Window)
+	}
+	b.history[program] = append(recent, now)
+	return nil
+}
@@ -0,0 +1,28 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestartBudget(t *testing.T) {
+	// Test restarts beyond budget are refused until window slides
+	// 测试超出预算的重启会被拒绝，直到窗口滑过
+	budget := supervisordkratos.NewRestartBudget(2, 10*time.Minute)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, budget.Allow("api", start))
+	require.NoError(t, budget.Allow("api", start.Add(time.Minute)))
+	err := budget.Allow("api", start.Add(2*time.Minute))
+	require.ErrorIs(t, err, supervisordkratos.ErrRestartBudgetExceeded)
+	t.Log(err)
+
+	// Other programs keep their own budget
+	// 其他程序拥有各自的预算
+	require.NoError(t, budget.Allow("worker", start.Add(2*time.Minute)))
+
+	require.NoError(t, budget.Allow("api", start.Add(10*time.Minute)))
+}
@@ -0,0 +1,62 @@
+package supervisordkratos
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/yyle88/must"
+)
+
+// ErrHostOversubscribed definitions declare more resources than host capacity
+// ErrHostOversubscribed 定义声明的资源超过主机容量
+var ErrHostOversubscribed = errors.New("host oversubscribed")
+
+// ErrCapacityIncomplete some programs lack resource annotations, so the capacity check could not count them
+// ErrCapacityIncomplete 部分程序缺少资源注解，容量检查无法统计它们
+var ErrCapacityIncomplete = errors.New("capacity check incomplete")
+
+// SumResources totals declared resources of programs, each annotation counted per instance
+// Disabled programs are skipped, names lacking annotations are returned for follow-up
+//
+// SumResources 汇总程序声明的资源，每个注解按实例数计算
+// 跳过停放的程序，并返回缺少注解的程序名称以便跟进
+func SumResources(programs ...*ProgramConfig) (*Resources, []string) {
+	total := &Resources{}
+	unannotated := make([]string, 0)
+	for _, program := range programs {
+		if program.Disabled.Get() {
+			continue
+		}
+		if !program.Resources.IsSet() {
+			unannotated = append(unannotated, program.Name)
+			continue
+		}
+		resources := program.Resources.Get()
+		total.CPUMillis += resources.CPUMillis * program.NumProcs.Get()
+		total.MemoryBytes += resources.MemoryBytes * int64(program.NumProcs.Get())
+	}
+	return total, unannotated
+}
+
+// CheckCapacity preflight comparing declared resources against host capacity before apply
+// Returns ErrHostOversubscribed when CPU or memory total exceeds capacity,
+// otherwise ErrCapacityIncomplete naming programs without annotations
+//
+// CheckCapacity 在应用前比较声明的资源与主机容量的预检
+// CPU 或内存总量超过容量时返回 ErrHostOversubscribed，
+// 否则在存在缺少注解的程序时返回带有其名称的 ErrCapacityIncomplete
+func CheckCapacity(capacity *Resources, programs ...*ProgramConfig) error {
+	must.Full(capacity)
+
+	total, unannotated := SumResources(programs...)
+	if total.CPUMillis > capacity.CPUMillis {
+		return errors.Wrapf(ErrHostOversubscribed, "cpu_millis %d exceeds capacity %d", total.CPUMillis, capacity.CPUMillis)
+	}
+	if total.MemoryBytes > capacity.MemoryBytes {
+		return errors.Wrapf(ErrHostOversubscribed, "memory_bytes %d exceeds capacity %d", total.MemoryBytes, capacity.MemoryBytes)
+	}
+	if len(unannotated) > 0 {
+		return errors.Wrapf(ErrCapacityIncomplete, "programs without resources: %s", strings.Join(unannotated, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCapacity(t *testing.T) {
+	// Test instance counts multiply annotations and oversubscription is reported
+	// 测试实例数量乘以注解，并报告超额订阅
+	api := supervisordkratos.NewProgramConfig(
+		"api",
+		"/opt/api",
+		"deploy",
+		"/var/log/api",
+	).WithNumProcs(4).FixProcessName().WithResources(500, 512<<20)
+
+	worker := supervisordkratos.NewProgramConfig(
+		"worker",
+		"/opt/worker",
+		"deploy",
+		"/var/log/worker",
+	).WithResources(1000, 1<<30)
+
+	parked := supervisordkratos.NewProgramConfig(
+		"parked",
+		"/opt/parked",
+		"deploy",
+		"/var/log/parked",
+	).WithResources(8000, 8<<30).WithDisabled(true)
+
+	legacy := supervisordkratos.NewProgramConfig(
+		"legacy",
+		"/opt/legacy",
+		"deploy",
+		"/var/log/legacy",
+	)
+
+	total, unannotated := supervisordkratos.SumResources(api, worker, parked, legacy)
+	t.Log(total.Comment())
+	require.Equal(t, 3000, total.CPUMillis)
+	require.Equal(t, int64(3<<30), total.MemoryBytes)
+	require.Equal(t, []string{"legacy"}, unannotated)
+
+	require.NoError(t, supervisordkratos.CheckCapacity(&supervisordkratos.Resources{CPUMillis: 4000, MemoryBytes: 4 << 30}, api, worker, parked))
+
+	// Fitting capacity still reports programs it could not count
+	// 容量足够时仍报告无法统计的程序
+	err := supervisordkratos.CheckCapacity(&supervisordkratos.Resources{CPUMillis: 4000, MemoryBytes: 4 << 30}, api, worker, parked, legacy)
+	require.ErrorIs(t, err, supervisordkratos.ErrCapacityIncomplete)
+	require.ErrorContains(t, err, "programs without resources: legacy")
+
+	err = supervisordkratos.CheckCapacity(&supervisordkratos.Resources{CPUMillis: 2000, MemoryBytes: 4 << 30}, api, worker)
+	require.ErrorIs(t, err, supervisordkratos.ErrHostOversubscribed)
+	require.ErrorContains(t, err, "cpu_millis 3000 exceeds capacity 2000")
+}
@@ -0,0 +1,126 @@
+package supervisordkratos
+
+import (
+	"strconv"
+	"strings"
+)
+
+// OptionChange one effective option that differs between two versions
+// Blank Old/New means the option is absent on that side
+//
+// OptionChange 两个版本之间不同的单个有效选项
+// Old/New 为空表示该侧不存在此选项
+type OptionChange struct {
+	Key string // Option name // 选项名称
+	Old string // Value in old version // 旧版本中的值
+	New string // Value in new version // 新版本中的值
+}
+
+// ProgramChange option changes of one program present in both versions
+// ProgramChange 两个版本中都存在的程序的选项变更
+type ProgramChange struct {
+	Name     string          // Program name // 程序名称
+	NumProcs int             // Instances in new version // 新版本中的实例数
+	Changes  []*OptionChange // Changed options in EffectiveOptions order // 按 EffectiveOptions 顺序的变更选项
+}
+
+// ComparisonReport high-level differences between two definition versions
+// ComparisonReport 两个定义版本之间的高层差异
+type ComparisonReport struct {
+	Added   []string         // Programs only in new version // 仅在新版本中的程序
+	Removed []string         // Programs only in old version // 仅在旧版本中的程序
+	Changed []*ProgramChange // Programs whose effective options differ // 有效选项不同的程序
+}
+
+// CompareDefinitions compares programs of two registries by effective options
+// Added and changed programs follow new order, removed ones follow old order
+//
+// CompareDefinitions 按有效选项比较两个注册表中的程序
+// 新增和变更的程序按新版本顺序，删除的程序按旧版本顺序
+func CompareDefinitions(oldRegistry *Registry, newRegistry *Registry) *ComparisonReport {
+	report := &ComparisonReport{}
+	for _, program := range newRegistry.Programs() {
+		previous, ok := oldRegistry.LookupProgram(program.Name)
+		if !ok {
+			report.Added = append(report.Added, program.Name)
+			continue
+		}
+		if changes := compareOptions(EffectiveOptions(previous), EffectiveOptions(program)); len(changes) > 0 {
+			report.Changed = append(report.Changed, &ProgramChange{
+				Name:     program.Name,
+				NumProcs: program.NumProcs.Get(),
+				Changes:  changes,
+			})
+		}
+	}
+	for _, program := range oldRegistry.Programs() {
+		if _, ok := newRegistry.LookupProgram(program.Name); !ok {
+			report.Removed = append(report.Removed, program.Name)
+		}
+	}
+	return report
+}
+
+// compareOptions lists options that differ, new keys first in new order then dropped keys
+// compareOptions 列出不同的选项，先按新顺序列出新键，再列出被删除的键
+func compareOptions(oldOptions []*Entry, newOptions []*Entry) []*OptionChange {
+	oldValues := make(map[string]string, len(oldOptions))
+	for _, entry := range oldOptions {
+		oldValues[entry.Key] = entry.Value
+	}
+	seen := make(map[string]bool, len(newOptions))
+	var changes []*OptionChange
+	for _, entry := range newOptions {
+		seen[entry.Key] = true
+		if value, ok := oldValues[entry.Key]; !ok || value != entry.Value {
+			changes = append(changes, &OptionChange{Key: entry.Key, Old: value, New: entry.Value})
+		}
+	}
+	for _, entry := range oldOptions {
+		if !seen[entry.Key] {
+			changes = append(changes, &OptionChange{Key: entry.Key, Old: entry.Value, New: ""})
+		}
+	}
+	return changes
+}
+
+// RestartEstimate counts processes supervisorctl update restarts for changed programs
+// Cosmetic changes are skipped, see ClassifyOption
+//
+// RestartEstimate 统计 supervisorctl update 因程序变更而重启的进程数
+// 外观变更不计入，参见 ClassifyOption
+func (r *ComparisonReport) RestartEstimate() int {
+	count := 0
+	for _, change := range r.Changed {
+		if change.Impact() == ImpactRestart {
+			count += change.NumProcs
+		}
+	}
+	return count
+}
+
+// HasChanges reports whether the two versions differ
+// HasChanges 报告两个版本是否存在差异
+func (r *ComparisonReport) HasChanges() bool {
+	return len(r.Added) > 0 || len(r.Removed) > 0 || len(r.Changed) > 0
+}
+
+// String renders report as plain text suitable for release notes
+// String 将报告渲染为适用于发布说明的纯文本
+func (r *ComparisonReport) String() string {
+	var sb strings.Builder
+	for _, name := range r.Added {
+		sb.WriteString("added: " + name + "\n")
+	}
+	for _, name := range r.Removed {
+		sb.WriteString("removed: " + name + "\n")
+	}
+	for _, change := range r.Changed {
+		sb.WriteString("changed: " + change.Name + " (" + string(change.Impact()) + ")\n")
+		for _, option := range change.Changes {
+			sb.WriteString("    " + option.Key + ": " + strconv.Quote(option.Old) + " -> " + strconv.Quote(option.New) + "\n")
+		}
+	}
+	sb.WriteString("restart: " + strconv.Itoa(r.RestartEstimate()) + " processes\n")
+	return sb.String()
+}
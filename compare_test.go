@@ -0,0 +1,40 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareDefinitions(t *testing.T) {
+	// Test report lists added/removed programs and option changes
+	// 测试报告列出新增/删除的程序和选项变更
+	oldRegistry := supervisordkratos.NewRegistry().
+		AddProgram(supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/api")).
+		AddProgram(supervisordkratos.NewProgramConfig("legacy", "/opt/legacy", "deploy", "/var/log/legacy"))
+	newRegistry := supervisordkratos.NewRegistry().
+		AddProgram(supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/api").
+			WithStartSecs(5).
+			WithNumProcs(2).
+			FixProcessName()).
+		AddGroup(supervisordkratos.NewGroupConfig("jobs").
+			AddProgram(supervisordkratos.NewProgramConfig("worker", "/opt/worker", "deploy", "/var/log/worker")))
+
+	report := supervisordkratos.CompareDefinitions(oldRegistry, newRegistry)
+	content := report.String()
+	t.Log(content)
+
+	const expected = `added: worker
+removed: legacy
+changed: api (restart)
+    startsecs: "1" -> "5"
+    numprocs: "1" -> "2"
+    process_name: "%(program_name)s" -> "%(program_name)s_%(process_num)02d"
+restart: 2 processes
+`
+	require.Equal(t, expected, content)
+	require.True(t, report.HasChanges())
+
+	require.False(t, supervisordkratos.CompareDefinitions(oldRegistry, oldRegistry).HasChanges())
+}
@@ -0,0 +1,125 @@
+package supervisordkratos
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/yyle88/must"
+	"github.com/yyle88/printgo"
+)
+
+// ctlCommands supervisorctl commands offered for the first word
+// ctlCommands 为第一个单词提供的 supervisorctl 命令
+var ctlCommands = []string{"status", "start", "stop", "restart", "tail", "pid", "signal"}
+
+// SupervisorctlTargets list supervisorctl targets a rendered config provides
+// Groups as "g:" and "g:member", standalone programs as "name", multi-instance ones as "name:*"
+//
+// SupervisorctlTargets 列出渲染后的配置提供的 supervisorctl 目标
+// 组为 "g:" 和 "g:member"，独立程序为 "name"，多实例程序为 "name:*"
+func SupervisorctlTargets(configText string) ([]string, error) {
+	document, err := ParseDocument(configText)
+	if err != nil {
+		return nil, err
+	}
+	members := make(map[string]bool)
+	targets := make([]string, 0, len(document.Sections))
+	for _, section := range document.Sections {
+		group, ok := strings.CutPrefix(section.Name, "group:")
+		if !ok {
+			continue
+		}
+		targets = append(targets, group+":")
+		programs, _ := section.Lookup("programs")
+		for _, name := range strings.Split(programs, ",") {
+			name = strings.TrimSpace(name)
+			members[name] = true
+			program, ok := document.Lookup("program:" + name)
+			if !ok {
+				return nil, errors.Errorf("group %s: program section program:%s does not exist", group, name)
+			}
+			// Members are addressed by process name, only simple templates are listed
+			// 成员按进程名访问，只列出简单模板
+			if processName, ok := processNameOf(program, name); ok {
+				targets = append(targets, group+":"+processName)
+			}
+		}
+	}
+	for _, section := range document.Sections {
+		name, ok := strings.CutPrefix(section.Name, "program:")
+		if !ok || members[name] {
+			continue
+		}
+		if numProcs, _ := section.Lookup("numprocs"); numProcs != "" && numProcs != "1" {
+			targets = append(targets, name+":*")
+		} else {
+			targets = append(targets, name)
+		}
+	}
+	return targets, nil
+}
+
+// processNameOf expand process_name of single-instance program, false when not expandable offline
+// processNameOf 展开单实例程序的 process_name，无法离线展开时返回 false
+func processNameOf(section *Section, name string) (string, bool) {
+	if numProcs, _ := section.Lookup("numprocs"); numProcs != "" && numProcs != "1" {
+		return "", false
+	}
+	processName, ok := section.Lookup("process_name")
+	if !ok {
+		return name, true
+	}
+	processName = strings.ReplaceAll(processName, "%(program_name)s", name)
+	return processName, !strings.Contains(processName, "%(")
+}
+
+// GenerateShellCompletion generate bash snippet defining function command wrapping supervisorctl
+// with tab-completion of exactly the targets in configText
+//
+// GenerateShellCompletion 生成 bash 片段，定义包装 supervisorctl 的函数 command，
+// 并精确补全 configText 中的目标
+func GenerateShellCompletion(configText string, command string, supervisorctl string) (string, error) {
+	must.Nice(supervisorctl)
+	if !isShellName(command) {
+		return "", errors.Errorf("invalid shell function name %q", command)
+	}
+	targets, err := SupervisorctlTargets(configText)
+	if err != nil {
+		return "", err
+	}
+	slices.Sort(targets)
+
+	ptx := printgo.NewPTX()
+	ptx.Println("# Managed by supervisordkratos: supervisorctl targets for " + command)
+	ptx.Println(command + "() { " + supervisorctl + " \"$@\"; }")
+	ptx.Println("_" + command + "_complete() {")
+	ptx.Println("    local cur=\"${COMP_LINE:0:COMP_POINT}\"")
+	ptx.Println("    cur=\"${cur##* }\"")
+	ptx.Println("    if [ \"$COMP_CWORD\" -le 1 ]; then")
+	ptx.Println("        COMPREPLY=($(compgen -W " + strconv.Quote(strings.Join(ctlCommands, " ")) + " -- \"$cur\"))")
+	ptx.Println("        return")
+	ptx.Println("    fi")
+	ptx.Println("    COMPREPLY=($(compgen -W " + strconv.Quote(strings.Join(targets, " ")) + " -- \"$cur\"))")
+	ptx.Println("    # ':' splits words in bash, drop the part already typed before it")
+	ptx.Println("    local prefix=\"${cur%\"${cur##*:}\"}\"")
+	ptx.Println("    COMPREPLY=(\"${COMPREPLY[@]#\"$prefix\"}\")")
+	ptx.Println("}")
+	ptx.Println("complete -F _" + command + "_complete " + command)
+	return ptx.String(), nil
+}
+
+// isShellName reports whether name is safe as bash function name
+// isShellName 报告名称是否可以安全地用作 bash 函数名
+func isShellName(name string) bool {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+	for _, c := range name {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
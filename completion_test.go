@@ -0,0 +1,55 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupervisorctlTargets(t *testing.T) {
+	// Test targets of groups, members and standalone programs
+	// 测试组、成员和独立程序的目标
+	group := supervisordkratos.NewGroupConfig("payments").
+		AddProgram(supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/payments")).
+		AddProgram(supervisordkratos.NewProgramConfig("worker", "/opt/worker", "deploy", "/var/log/payments").
+			WithNumProcs(2).
+			FixProcessName())
+	monitor := supervisordkratos.NewProgramConfig("monitor", "/opt/monitor", "deploy", "/var/log/monitor")
+
+	content := supervisordkratos.GenerateGroupConfig(group) + "\n" + supervisordkratos.GenerateProgramConfig(monitor)
+	targets, err := supervisordkratos.SupervisorctlTargets(content)
+	require.NoError(t, err)
+	require.Equal(t, []string{"payments:", "payments:api", "monitor"}, targets)
+}
+
+func TestGenerateShellCompletion(t *testing.T) {
+	// Test bash snippet wraps supervisorctl and completes targets
+	// 测试 bash 片段包装 supervisorctl 并补全目标
+	program := supervisordkratos.NewProgramConfig("monitor", "/opt/monitor", "deploy", "/var/log/monitor").WithAlias("mon")
+
+	content, err := supervisordkratos.GenerateShellCompletion(supervisordkratos.GenerateProgramConfig(program), "sctl", "sudo supervisorctl")
+	require.NoError(t, err)
+	t.Log(content)
+
+	const expected = `# Managed by supervisordkratos: supervisorctl targets for sctl
+sctl() { sudo supervisorctl "$@"; }
+_sctl_complete() {
+    local cur="${COMP_LINE:0:COMP_POINT}"
+    cur="${cur##* }"
+    if [ "$COMP_CWORD" -le 1 ]; then
+        COMPREPLY=($(compgen -W "status start stop restart tail pid signal" -- "$cur"))
+        return
+    fi
+    COMPREPLY=($(compgen -W "mon: mon:monitor" -- "$cur"))
+    # ':' splits words in bash, drop the part already typed before it
+    local prefix="${cur%"${cur##*:}"}"
+    COMPREPLY=("${COMPREPLY[@]#"$prefix"}")
+}
+complete -F _sctl_complete sctl
+`
+	require.Equal(t, expected, content)
+
+	_, err = supervisordkratos.GenerateShellCompletion("", "bad-name", "supervisorctl")
+	require.Error(t, err)
+}
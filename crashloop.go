@@ -0,0 +1,157 @@
+package supervisordkratos
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/yyle88/must"
+)
+
+// supervisordLogTime timestamp layout of supervisord main log lines
+// supervisordLogTime supervisord 主日志行的时间戳格式
+const supervisordLogTime = "2006-01-02 15:04:05,000"
+
+// supervisordLogPattern matches spawned/exited/gave up lines of supervisord main log
+// supervisordLogPattern 匹配 supervisord 主日志中的 spawned/exited/gave up 行
+var supervisordLogPattern = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2},\d{3}) \w+ (spawned|exited|gave up): '?([^' ]+)'?(.*)$`)
+
+// CrashLoop one process that failed repeatedly, with a retry settings hint
+// CrashLoop 反复失败的单个进程，附带重试设置提示
+type CrashLoop struct {
+	Process       string // Process name as logged // 日志中的进程名称
+	StartFailures int    // Exits before startsecs, each one enters BACKOFF // 在 startsecs 之前退出的次数，每次进入 BACKOFF
+	Crashes       int    // Unexpected exits after startsecs // 在 startsecs 之后的非预期退出次数
+	Fatal         bool   // Gave up and entered FATAL // 已放弃并进入 FATAL
+	Suggestion    string // Hint correlated with generated config, blank when unknown // 与生成配置关联的提示，未知时为空
+}
+
+// DetectCrashLoops scan supervisord main log and report processes whose unexpected exits
+// reach threshold within window, or that entered FATAL
+// Programs give startsecs/startretries, a process matches one of their expanded process names
+//
+// DetectCrashLoops 扫描 supervisord 主日志，报告在 window 内非预期退出次数达到 threshold
+// 或进入 FATAL 的进程
+// programs 提供 startsecs/startretries，进程按其展开后的进程名称匹配
+func DetectCrashLoops(logText string, window time.Duration, threshold int, programs ...*ProgramConfig) ([]*CrashLoop, error) {
+	must.True(window > 0)
+	must.True(threshold > 0)
+
+	spawned := make(map[string]time.Time)
+	exits := make(map[string][]time.Time)
+	loops := make(map[string]*CrashLoop)
+	var order []string
+	loopOf := func(process string) *CrashLoop {
+		if loop, ok := loops[process]; ok {
+			return loop
+		}
+		loops[process] = &CrashLoop{Process: process}
+		order = append(order, process)
+		return loops[process]
+	}
+
+	for idx, line := range strings.Split(logText, "\n") {
+		match := supervisordLogPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		at, err := time.Parse(supervisordLogTime, match[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", idx+1)
+		}
+		process := match[3]
+		switch match[2] {
+		case "spawned":
+			spawned[process] = at
+		case "exited":
+			if !strings.Contains(match[4], "not expected") {
+				continue
+			}
+			exits[process] = append(exits[process], at)
+			loop := loopOf(process)
+			if at.Sub(spawned[process]) < time.Duration(startSecsOf(process, programs))*time.Second {
+				loop.StartFailures++
+			} else {
+				loop.Crashes++
+			}
+		case "gave up":
+			loopOf(process).Fatal = true
+		}
+	}
+
+	results := make([]*CrashLoop, 0, len(order))
+	for _, process := range order {
+		loop := loops[process]
+		if !loop.Fatal && maxWithin(exits[process], window) < threshold {
+			continue
+		}
+		loop.Suggestion = suggestCrashFix(loop, programOf(process, programs))
+		results = append(results, loop)
+	}
+	return results, nil
+}
+
+// maxWithin counts the most sorted times falling inside any window-long span
+// maxWithin 统计任意 window 长度区间内包含的最多有序时间点数
+func maxWithin(times []time.Time, window time.Duration) int {
+	best := 0
+	start := 0
+	for end := range times {
+		for times[end].Sub(times[start]) >= window {
+			start++
+		}
+		best = max(best, end-start+1)
+	}
+	return best
+}
+
+// programOf find program owning logged process name, nil when none
+// Matches expanded process_name instances, templates unknown offline fall back to name_ prefix
+//
+// programOf 查找拥有日志中进程名称的程序，没有时返回 nil
+// 匹配展开后的 process_name 实例，无法离线展开的模板退回到 name_ 前缀匹配
+func programOf(process string, programs []*ProgramConfig) *ProgramConfig {
+	for _, program := range programs {
+		if processNames, err := program.ProcessNames(); err == nil {
+			if slices.Contains(processNames, process) {
+				return program
+			}
+			continue
+		}
+		if process == program.Name || (program.NumProcs.Get() > 1 && strings.HasPrefix(process, program.Name+"_")) {
+			return program
+		}
+	}
+	return nil
+}
+
+// startSecsOf startsecs of process program, supervisord default when unknown
+// startSecsOf 进程所属程序的 startsecs，未知时使用 supervisord 默认值
+func startSecsOf(process string, programs []*ProgramConfig) int {
+	if program := programOf(process, programs); program != nil {
+		return program.StartSecs.Get()
+	}
+	return 1
+}
+
+// suggestCrashFix explain loop in terms of program retry settings
+// suggestCrashFix 根据程序重试设置解释崩溃循环
+func suggestCrashFix(loop *CrashLoop, program *ProgramConfig) string {
+	if program == nil {
+		return ""
+	}
+	switch {
+	case loop.Fatal && loop.StartFailures > 0:
+		return "exits before startsecs=" + strconv.Itoa(program.StartSecs.Get()) + " and gave up after startretries=" + strconv.Itoa(program.StartRetries.Get()) +
+			", raise startsecs if bootstrap is slow or size retries with AdviseRetry"
+	case loop.StartFailures > 0:
+		return "exits before startsecs=" + strconv.Itoa(program.StartSecs.Get()) + ", check bootstrap time against startsecs"
+	case formatAutoRestart(program.AutoRestart.Get()) == "true":
+		return "crashes after start and autorestart=true restarts it forever, consider autorestart=unexpected with fixed exitcodes"
+	default:
+		return "crashes after start and autorestart restarts it, check program logs"
+	}
+}
@@ -0,0 +1,71 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectCrashLoops(t *testing.T) {
+	// Test start failures reaching FATAL and runtime crashes are reported
+	// 测试到达 FATAL 的启动失败和运行时崩溃都会被报告
+	const logText = `2024-01-01 10:00:00,000 INFO spawned: 'api' with pid 100
+2024-01-01 10:00:01,000 INFO exited: api (exit status 1; not expected)
+2024-01-01 10:00:02,000 INFO spawned: 'api' with pid 101
+2024-01-01 10:00:03,000 INFO exited: api (exit status 1; not expected)
+2024-01-01 10:00:05,000 INFO spawned: 'api' with pid 102
+2024-01-01 10:00:06,000 INFO exited: api (exit status 1; not expected)
+2024-01-01 10:00:06,100 INFO gave up: api entered FATAL state, too many start retries too quickly
+2024-01-01 10:00:00,000 INFO spawned: 'worker_00' with pid 200
+2024-01-01 10:00:10,000 INFO success: worker_00 entered RUNNING state, process has stayed up for > than 3 seconds (startsecs)
+2024-01-01 10:01:00,000 INFO exited: worker_00 (exit status 2; not expected)
+2024-01-01 10:01:01,000 INFO spawned: 'worker_00' with pid 201
+2024-01-01 10:02:00,000 INFO exited: worker_00 (exit status 2; not expected)
+2024-01-01 10:02:01,000 INFO spawned: 'worker_00' with pid 202
+2024-01-01 10:03:00,000 INFO exited: worker_00 (exit status 2; not expected)
+2024-01-01 10:00:00,000 INFO spawned: 'cron' with pid 300
+2024-01-01 10:00:30,000 INFO exited: cron (exit status 0; expected)
+`
+	api := supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/api").WithStartSecs(3).WithStartRetries(2)
+	worker := supervisordkratos.NewProgramConfig("worker", "/opt/worker", "deploy", "/var/log/worker").
+		WithStartSecs(3).
+		WithNumProcs(2).
+		FixProcessName().
+		WithAutoRestart(true)
+
+	loops, err := supervisordkratos.DetectCrashLoops(logText, 5*time.Minute, 3, api, worker)
+	require.NoError(t, err)
+	for _, loop := range loops {
+		t.Log(loop.Process, loop.Suggestion)
+	}
+
+	require.Len(t, loops, 2)
+	require.Equal(t, "api", loops[0].Process)
+	require.Equal(t, 3, loops[0].StartFailures)
+	require.True(t, loops[0].Fatal)
+	require.Contains(t, loops[0].Suggestion, "startretries=2")
+
+	require.Equal(t, "worker_00", loops[1].Process)
+	require.Equal(t, 3, loops[1].Crashes)
+	require.False(t, loops[1].Fatal)
+	require.Contains(t, loops[1].Suggestion, "autorestart=true")
+
+	// Narrow window keeps spread-out crashes under threshold
+	// 较窄的窗口使分散的崩溃低于阈值
+	loops, err = supervisordkratos.DetectCrashLoops(logText, time.Minute, 3, api, worker)
+	require.NoError(t, err)
+	require.Len(t, loops, 1)
+
+	// Instance of another program sharing the name prefix gets no suggestion
+	// 共享名称前缀的其他程序实例不会得到建议
+	const gatewayLog = `2024-01-01 10:00:00,000 INFO spawned: 'worker-gateway_00' with pid 400
+2024-01-01 10:00:01,000 INFO exited: worker-gateway_00 (exit status 1; not expected)
+2024-01-01 10:00:01,100 INFO gave up: worker-gateway_00 entered FATAL state, too many start retries too quickly
+`
+	loops, err = supervisordkratos.DetectCrashLoops(gatewayLog, time.Minute, 3, api, worker)
+	require.NoError(t, err)
+	require.Len(t, loops, 1)
+	require.Empty(t, loops[0].Suggestion)
+}
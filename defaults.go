@@ -0,0 +1,123 @@
+package supervisordkratos
+
+import (
+	"encoding/json"
+	"reflect"
+	"runtime/debug"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// modulePath import path used to find package version in build info
+// modulePath 用于在构建信息中查找包版本的导入路径
+const modulePath = "github.com/orzkratos/supervisordkratos"
+
+// programOptionKeys [program:x] option written by each ProgramConfig Opt field, fields without one are package-only
+// programOptionKeys 每个 ProgramConfig Opt 字段写入的 [program:x] 选项，没有选项的字段仅在包内使用
+var programOptionKeys = map[string]string{
+	"Environment":    "environment",
+	"AutoStart":      "autostart",
+	"AutoRestart":    "autorestart",
+	"StartRetries":   "startretries",
+	"StartSecs":      "startsecs",
+	"LogMaxBytes":    "stdout_logfile_maxbytes",
+	"LogBackups":     "stdout_logfile_backups",
+	"RedirectStderr": "redirect_stderr",
+	"StopAsGroup":    "stopasgroup",
+	"StopWaitSecs":   "stopwaitsecs",
+	"KillAsGroup":    "killasgroup",
+	"StopSignal":     "stopsignal",
+	"Priority":       "priority",
+	"ExitCodes":      "exitcodes",
+	"NumProcs":       "numprocs",
+	"ProcessName":    "process_name",
+}
+
+// supervisordOptionKeys [supervisord] option written by each SupervisordConfig Opt field
+// supervisordOptionKeys 每个 SupervisordConfig Opt 字段写入的 [supervisord] 选项
+var supervisordOptionKeys = map[string]string{
+	"Environment": "environment",
+	"MinFds":      "minfds",
+	"MinProcs":    "minprocs",
+	"LogLevel":    "loglevel",
+	"LogMaxBytes": "logfile_maxbytes",
+	"LogBackups":  "logfile_backups",
+	"ChildLogDir": "childlogdir",
+	"NoCleanup":   "nocleanup",
+	"Directory":   "directory",
+	"Identifier":  "identifier",
+}
+
+// DefaultEntry default value of one Opt field
+// DefaultEntry 单个 Opt 字段的默认值
+type DefaultEntry struct {
+	Field   string `json:"field"`            // Go field name // Go 字段名称
+	Option  string `json:"option,omitempty"` // supervisord option, blank when package-only // supervisord 选项，仅包内使用时为空
+	Default any    `json:"default"`          // Constructor default // 构造函数默认值
+}
+
+// DefaultsTable constructor defaults of this package version
+// DefaultsTable 当前包版本的构造函数默认值
+type DefaultsTable struct {
+	Version     string          `json:"version"`     // Module version from build info // 来自构建信息的模块版本
+	Program     []*DefaultEntry `json:"program"`     // NewProgramConfig defaults // NewProgramConfig 默认值
+	Supervisord []*DefaultEntry `json:"supervisord"` // NewSupervisordConfig defaults // NewSupervisordConfig 默认值
+}
+
+// NewDefaultsTable introspect NewProgramConfig and NewSupervisordConfig Opt fields in declaration order
+// NewDefaultsTable 按声明顺序内省 NewProgramConfig 和 NewSupervisordConfig 的 Opt 字段
+func NewDefaultsTable() *DefaultsTable {
+	return &DefaultsTable{
+		Version:     moduleVersion(),
+		Program:     optDefaults(NewProgramConfig("name", "/root", "user", "/slog"), programOptionKeys),
+		Supervisord: optDefaults(NewSupervisordConfig(), supervisordOptionKeys),
+	}
+}
+
+// GenerateDefaultsJSON render DefaultsTable as indented JSON
+// GenerateDefaultsJSON 将 DefaultsTable 渲染为缩进的 JSON
+func GenerateDefaultsJSON() (string, error) {
+	data, err := json.MarshalIndent(NewDefaultsTable(), "", "  ")
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	return string(data) + "\n", nil
+}
+
+// optDefaults collect Default() of every *Opt[T] field of config struct pointer, options named by optionKeys
+// optDefaults 收集配置结构体指针中每个 *Opt[T] 字段的 Default() 值，选项名称来自 optionKeys
+func optDefaults(config any, optionKeys map[string]string) []*DefaultEntry {
+	value := reflect.ValueOf(config).Elem()
+	results := make([]*DefaultEntry, 0, value.NumField())
+	for idx := range value.NumField() {
+		field := value.Type().Field(idx)
+		if !field.IsExported() || !strings.HasPrefix(field.Type.String(), "*supervisordkratos.Opt[") {
+			continue
+		}
+		results = append(results, &DefaultEntry{
+			Field:   field.Name,
+			Option:  optionKeys[field.Name],
+			Default: value.Field(idx).MethodByName("Default").Call(nil)[0].Interface(),
+		})
+	}
+	return results
+}
+
+// moduleVersion version of this module in the running binary, "(devel)" when built from source
+// moduleVersion 运行中的二进制里本模块的版本，源码构建时为 "(devel)"
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(unknown)"
+	}
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			return dep.Version
+		}
+	}
+	return "(devel)"
+}
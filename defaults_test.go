@@ -0,0 +1,47 @@
+package supervisordkratos_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDefaultsTable(t *testing.T) {
+	// Test defaults table lists Opt fields with supervisord defaults
+	// 测试默认值表列出带有 supervisord 默认值的 Opt 字段
+	table := supervisordkratos.NewDefaultsTable()
+	require.NotEmpty(t, table.Version)
+
+	defaults := make(map[string]*supervisordkratos.DefaultEntry)
+	for _, entry := range table.Program {
+		defaults[entry.Field] = entry
+	}
+	require.Equal(t, "startretries", defaults["StartRetries"].Option)
+	require.Equal(t, 3, defaults["StartRetries"].Default)
+	require.Equal(t, "TERM", defaults["StopSignal"].Default)
+	require.Equal(t, "", defaults["Disabled"].Option)
+	require.Equal(t, "minfds", table.Supervisord[1].Option)
+
+	// Same field names map to [supervisord] options, not [program:x] ones
+	// 相同的字段名称映射到 [supervisord] 选项，而不是 [program:x] 选项
+	supervisordDefaults := make(map[string]*supervisordkratos.DefaultEntry)
+	for _, entry := range table.Supervisord {
+		supervisordDefaults[entry.Field] = entry
+	}
+	require.Equal(t, "logfile_maxbytes", supervisordDefaults["LogMaxBytes"].Option)
+	require.Equal(t, "logfile_backups", supervisordDefaults["LogBackups"].Option)
+	require.Equal(t, "stdout_logfile_maxbytes", defaults["LogMaxBytes"].Option)
+	for _, entry := range table.Supervisord {
+		require.NotEmpty(t, entry.Option, entry.Field)
+	}
+
+	content, err := supervisordkratos.GenerateDefaultsJSON()
+	require.NoError(t, err)
+	t.Log(content)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal([]byte(content), &decoded))
+	require.Contains(t, decoded, "program")
+}
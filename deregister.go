@@ -0,0 +1,68 @@
+package supervisordkratos
+
+import (
+	"context"
+	"strings"
+
+	"github.com/yyle88/must"
+)
+
+// Deregisterer removes a stopping process from service discovery
+// Adapt Kratos registry.Registrar by mapping event to its ServiceInstance
+//
+// Deregisterer 将正在停止的进程从服务发现中移除
+// 将事件映射为 ServiceInstance 即可适配 Kratos registry.Registrar
+type Deregisterer interface {
+	Deregister(ctx context.Context, event *ProcessStateEvent) error
+}
+
+// DeregisterFunc adapts plain function to Deregisterer
+// DeregisterFunc 将普通函数适配为 Deregisterer
+type DeregisterFunc func(ctx context.Context, event *ProcessStateEvent) error
+
+// Deregister calls f(ctx, event)
+// Deregister 调用 f(ctx, event)
+func (f DeregisterFunc) Deregister(ctx context.Context, event *ProcessStateEvent) error {
+	return f(ctx, event)
+}
+
+// DeregisterEvents events the handler from NewDeregisterHandler needs subscribed
+// STOPPING covers graceful stops, EXITED covers crashes
+//
+// DeregisterEvents NewDeregisterHandler 返回的处理器需要订阅的事件
+// STOPPING 对应正常停止，EXITED 对应崩溃
+var DeregisterEvents = []string{"PROCESS_STATE_STOPPING", "PROCESS_STATE_EXITED"}
+
+// NewDeregisterHandler create EventHandler deregistering instances of programs on STOPPING/EXITED
+// supervisord sends STOPPING right after stopsignal, so stopwaitsecs bounds the time deregistration has
+// Events of other programs and other event types are acknowledged untouched
+//
+// NewDeregisterHandler 创建在 STOPPING/EXITED 时注销 programs 实例的 EventHandler
+// supervisord 在发送 stopsignal 后立即发出 STOPPING，因此 stopwaitsecs 限定了注销可用的时间
+// 其他程序的事件和其他类型的事件会直接确认，不做处理
+func NewDeregisterHandler(ctx context.Context, deregisterer Deregisterer, programs ...*ProgramConfig) EventHandler {
+	must.True(deregisterer != nil)
+	must.Have(programs)
+	return func(event *Event) error {
+		if !strings.HasPrefix(event.EventName(), "PROCESS_STATE_") {
+			return nil
+		}
+		state, err := event.ProcessState()
+		if err != nil {
+			return err
+		}
+		if state.ToState != ProcessStopping && state.ToState != ProcessExited {
+			return nil
+		}
+		if programOf(state.ProcessName, programs) == nil {
+			return nil
+		}
+		return deregisterer.Deregister(ctx, state)
+	}
+}
+
+// NewDeregisterListener build [eventlistener:x] section running listener subscribed to DeregisterEvents
+// NewDeregisterListener 构建运行 listener 并订阅 DeregisterEvents 的 [eventlistener:x] 段落
+func NewDeregisterListener(listener *ProgramConfig) *Section {
+	return NewEventListenerSection(listener, DeregisterEvents...)
+}
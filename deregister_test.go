@@ -0,0 +1,47 @@
+package supervisordkratos_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDeregisterHandler(t *testing.T) {
+	// Test STOPPING/EXITED of tracked programs trigger deregistration
+	// 测试被跟踪程序的 STOPPING/EXITED 触发注销
+	api := supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/api").
+		WithNumProcs(2).
+		FixProcessName()
+
+	var removed []string
+	handler := supervisordkratos.NewDeregisterHandler(context.Background(), supervisordkratos.DeregisterFunc(
+		func(ctx context.Context, event *supervisordkratos.ProcessStateEvent) error {
+			removed = append(removed, event.FullName()+"@"+strconv.Itoa(event.PID))
+			return nil
+		},
+	), api)
+
+	var stdin strings.Builder
+	for _, item := range []struct{ name, payload string }{
+		{"PROCESS_STATE_RUNNING", "processname:api_00 groupname:api from_state:STARTING pid:100"},
+		{"PROCESS_STATE_STOPPING", "processname:api_00 groupname:api from_state:RUNNING pid:100"},
+		{"PROCESS_STATE_EXITED", "processname:api_01 groupname:api from_state:RUNNING expected:0 pid:101"},
+		{"PROCESS_STATE_STOPPING", "processname:monitor groupname:monitor from_state:RUNNING pid:200"},
+		{"PROCESS_STATE_STOPPING", "processname:api-gateway_00 groupname:api-gateway from_state:RUNNING pid:300"},
+	} {
+		stdin.WriteString("ver:3.0 eventname:" + item.name + " len:" + strconv.Itoa(len(item.payload)) + "\n" + item.payload)
+	}
+	var stdout strings.Builder
+	require.NoError(t, supervisordkratos.RunEventListener(strings.NewReader(stdin.String()), &stdout, handler))
+	require.Equal(t, []string{"api:api_00@100", "api:api_01@101"}, removed)
+
+	content := supervisordkratos.NewDeregisterListener(
+		supervisordkratos.NewProgramConfig("deregister", "/opt/deregister", "deploy", "/var/log/deregister"),
+	).String()
+	t.Log(content)
+	require.Contains(t, content, "events          = PROCESS_STATE_STOPPING,PROCESS_STATE_EXITED\n")
+}
@@ -0,0 +1,70 @@
+package supervisordkratos
+
+import (
+	"github.com/yyle88/must"
+)
+
+// LogDiskUsage worst-case log disk consumption of one program
+// LogDiskUsage 单个程序日志的最坏磁盘占用
+type LogDiskUsage struct {
+	Program   string // Program name // 程序名称
+	Files     int    // Log files per instance, 1 when stderr is redirected // 每个实例的日志文件数，重定向 stderr 时为 1
+	Instances int    // Process instance count // 进程实例数量
+	Bytes     int64  // LogMaxBytes x (LogBackups+1) x Files x Instances // LogMaxBytes x (LogBackups+1) x Files x Instances
+	Unbounded bool   // maxbytes=0 turns rotation off, usage has no upper limit // maxbytes=0 关闭轮转，占用没有上限
+}
+
+// LogDiskReport per program and total worst-case log disk consumption of a group
+// LogDiskReport 组内每个程序及总计的最坏日志磁盘占用
+type LogDiskReport struct {
+	Programs   []*LogDiskUsage // Usage per program in group order // 按组内顺序的每个程序占用
+	TotalBytes int64           // Sum of bounded programs // 有上限程序的总和
+	Unbounded  []string        // Programs without rotation, left out of TotalBytes // 未轮转的程序，不计入 TotalBytes
+}
+
+// EstimateLogDiskUsage compute worst-case disk consumption of configured log retention
+// Standalone programs count too, disabled programs keep their rotated files so they are included
+//
+// EstimateLogDiskUsage 根据配置的日志保留计算最坏磁盘占用
+// 独立程序同样计入，停放的程序仍保留已轮转的文件，因此也包含在内
+func EstimateLogDiskUsage(group *GroupConfig) (*LogDiskReport, error) {
+	must.Full(group)
+
+	report := &LogDiskReport{
+		Programs:  make([]*LogDiskUsage, 0, len(group.Programs)+len(group.Standalone)),
+		Unbounded: make([]string, 0),
+	}
+	for _, program := range append(append([]*ProgramConfig{}, group.Programs...), group.Standalone...) {
+		usage, err := estimateProgramLogDisk(program)
+		if err != nil {
+			return nil, err
+		}
+		report.Programs = append(report.Programs, usage)
+		if usage.Unbounded {
+			report.Unbounded = append(report.Unbounded, usage.Program)
+			continue
+		}
+		report.TotalBytes += usage.Bytes
+	}
+	return report, nil
+}
+
+// estimateProgramLogDisk compute worst-case log disk consumption of one program
+// estimateProgramLogDisk 计算单个程序的最坏日志磁盘占用
+func estimateProgramLogDisk(program *ProgramConfig) (*LogDiskUsage, error) {
+	maxBytes, err := parseByteSize(program.LogMaxBytes.Get())
+	if err != nil {
+		return nil, err
+	}
+	usage := &LogDiskUsage{
+		Program:   program.Name,
+		Files:     2,
+		Instances: program.NumProcs.Get(),
+		Unbounded: maxBytes == 0,
+	}
+	if program.RedirectStderr.Get() {
+		usage.Files = 1
+	}
+	usage.Bytes = maxBytes * int64(program.LogBackups.Get()+1) * int64(usage.Files*usage.Instances)
+	return usage, nil
+}
@@ -0,0 +1,47 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateLogDiskUsage(t *testing.T) {
+	// Test worst-case usage per program and total, with redirect and unbounded logs
+	// 测试每个程序及总计的最坏占用，包含重定向和无上限日志
+	group := supervisordkratos.NewGroupConfig("shop").
+		AddProgram(supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/api").
+			WithLogMaxBytes("10MB").WithLogBackups(3).WithNumProcs(2)).
+		AddProgram(supervisordkratos.NewProgramConfig("worker", "/opt/worker", "deploy", "/var/log/worker").
+			WithLogMaxBytes("1KB").WithLogBackups(4).WithRedirectStderr(true)).
+		AddStandaloneProgram(supervisordkratos.NewProgramConfig("debug", "/opt/debug", "deploy", "/var/log/debug").
+			WithLogMaxBytes("0"))
+
+	report, err := supervisordkratos.EstimateLogDiskUsage(group)
+	require.NoError(t, err)
+	require.Len(t, report.Programs, 3)
+
+	api := report.Programs[0]
+	require.Equal(t, 2, api.Files)
+	require.Equal(t, 2, api.Instances)
+	require.Equal(t, int64(10<<20*4*2*2), api.Bytes)
+
+	worker := report.Programs[1]
+	require.Equal(t, 1, worker.Files)
+	require.Equal(t, int64(1<<10*5), worker.Bytes)
+
+	require.True(t, report.Programs[2].Unbounded)
+	require.Equal(t, []string{"debug"}, report.Unbounded)
+	require.Equal(t, api.Bytes+worker.Bytes, report.TotalBytes)
+}
+
+func TestEstimateLogDiskUsageInvalidSize(t *testing.T) {
+	// Test invalid maxbytes value returns error
+	// 测试非法的 maxbytes 值返回错误
+	group := supervisordkratos.NewGroupConfig("shop").
+		AddProgram(supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/api").WithLogMaxBytes("10 MiB"))
+
+	_, err := supervisordkratos.EstimateLogDiskUsage(group)
+	require.Error(t, err)
+}
@@ -0,0 +1,162 @@
+package supervisordkratos
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/yyle88/must"
+	"github.com/yyle88/printgo"
+)
+
+// continuationIndent prefixes continuation lines of multi-line values
+// continuationIndent 多行值续行的前缀缩进
+const continuationIndent = "    "
+
+// Entry single key = value line inside a section
+// 段落中的单个 key = value 行
+type Entry struct {
+	Key      string   // Option name // 选项名称
+	Value    string   // Option value // 选项值
+	Comments []string // Comment lines above entry, without ';' prefix // 条目上方的注释行，不含 ';' 前缀
+}
+
+// Section single [name] block with ordered entries
+// Sits between config structs and text output, can be reordered/filtered before rendering
+//
+// Section 单个 [name] 块，包含有序的条目
+// 位于配置结构和文本输出之间，可在渲染前重新排序/过滤
+type Section struct {
+	Name     string   // Section name without brackets, e.g. "program:myapp" // 不含方括号的段名称
+	Entries  []*Entry // Ordered entries // 有序条目
+	Comments []string // Comment lines above header, without ';' prefix // 段头上方的注释行，不含 ';' 前缀
+	Compact  bool     // Render key=value without alignment // 渲染为 key=value 不对齐
+	Padding  int      // Extra blank lines rendered after section // 段落后额外渲染的空行数
+}
+
+// NewSection create new blank Section with name
+// 创建指定名称的空 Section
+func NewSection(name string) *Section {
+	return &Section{
+		Name:    must.Nice(name),
+		Entries: make([]*Entry, 0),
+	}
+}
+
+// Add append entry to section
+// 向段落追加条目
+func (s *Section) Add(key string, value string) *Section {
+	s.Entries = append(s.Entries, &Entry{Key: must.Nice(key), Value: value})
+	return s
+}
+
+// Lookup find entry value with key
+// 根据键查找条目值
+func (s *Section) Lookup(key string) (string, bool) {
+	for _, entry := range s.Entries {
+		if entry.Key == key {
+			return entry.Value, true
+		}
+	}
+	return "", false
+}
+
+// Set replace value of first entry with key in place, or append when missing
+// Set 就地替换第一个匹配键的条目值，不存在时追加
+func (s *Section) Set(key string, value string) *Section {
+	for _, entry := range s.Entries {
+		if entry.Key == key {
+			entry.Value = value
+			return s
+		}
+	}
+	return s.Add(key, value)
+}
+
+// Remove delete all entries with key
+// 删除指定键的所有条目
+func (s *Section) Remove(key string) *Section {
+	s.Entries = slices.DeleteFunc(s.Entries, func(entry *Entry) bool {
+		return entry.Key == key
+	})
+	return s
+}
+
+// String render section in supervisord INI format
+// String 以 supervisord INI 格式渲染段落
+func (s *Section) String() string {
+	ptx := printgo.NewPTX()
+	printComments(ptx, s.Comments)
+	ptx.Println("[" + s.Name + "]")
+	for _, entry := range s.Entries {
+		printComments(ptx, entry.Comments)
+		// Multi-line values continue on indented lines
+		// 多行值在缩进行上延续
+		value := strings.ReplaceAll(entry.Value, "\n", "\n"+continuationIndent)
+		if s.Compact {
+			ptx.Println(entry.Key + "=" + value)
+		} else {
+			ptx.Printf("%-15s = %s\n", entry.Key, value)
+		}
+	}
+	return ptx.String()
+}
+
+// Document ordered sections that render into one supervisord config file
+// Document 渲染为单个 supervisord 配置文件的有序段落
+type Document struct {
+	Sections []*Section // Ordered sections // 有序段落
+	Trailer  []string   // Comment lines after the last section // 最后一个段落之后的注释行
+}
+
+// NewDocument create new Document with sections
+// 创建包含段落的新 Document
+func NewDocument(sections ...*Section) *Document {
+	return &Document{Sections: append(make([]*Section, 0, len(sections)), sections...)}
+}
+
+// Add append sections to document
+// 向文档追加段落
+func (d *Document) Add(sections ...*Section) *Document {
+	d.Sections = append(d.Sections, sections...)
+	return d
+}
+
+// Lookup find section with name
+// 根据名称查找段落
+func (d *Document) Lookup(name string) (*Section, bool) {
+	for _, section := range d.Sections {
+		if section.Name == name {
+			return section, true
+		}
+	}
+	return nil, false
+}
+
+// String render document with blank line between sections
+// String 渲染文档，段落之间用空行分隔
+func (d *Document) String() string {
+	ptx := printgo.NewPTX()
+	for idx, section := range d.Sections {
+		if idx > 0 {
+			ptx.Println()
+			for range d.Sections[idx-1].Padding {
+				ptx.Println()
+			}
+		}
+		ptx.Print(section.String())
+	}
+	printComments(ptx, d.Trailer)
+	return ptx.String()
+}
+
+// printComments print comment lines with ';' prefix
+// printComments 打印带 ';' 前缀的注释行
+func printComments(ptx *printgo.PTX, comments []string) {
+	for _, comment := range comments {
+		if comment == "" {
+			ptx.Println(";")
+		} else {
+			ptx.Println("; " + comment)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgramSectionPostProcess(t *testing.T) {
+	// Test post-processing program section before rendering
+	// 测试渲染前对程序段落进行后续处理
+	program := supervisordkratos.NewProgramConfig(
+		"myapp",
+		"/opt/myapp",
+		"deploy",
+		"/var/log/myapp",
+	).WithPriority(100)
+
+	section := supervisordkratos.NewProgramSection(program)
+	require.Equal(t, supervisordkratos.GenerateProgramConfig(program), section.String())
+
+	value, ok := section.Lookup("priority")
+	require.True(t, ok)
+	require.Equal(t, "100", value)
+
+	section.Remove("priority").Add("startsecs", "5")
+
+	const expected = `[program:myapp]
+user            = deploy
+directory       = /opt/myapp
+command         = /opt/myapp/bin/myapp
+stdout_logfile  = /var/log/myapp/myapp.log
+stderr_logfile  = /var/log/myapp/myapp.err
+startsecs       = 5
+`
+
+	require.Equal(t, expected, section.String())
+}
+
+func TestGroupDocument(t *testing.T) {
+	// Test group document lookup and rendering
+	// 测试组文档查找和渲染
+	group := supervisordkratos.NewGroupConfig("services").
+		AddProgram(supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/services")).
+		AddProgram(supervisordkratos.NewProgramConfig("job", "/opt/job", "deploy", "/var/log/services"))
+
+	document := supervisordkratos.NewGroupDocument(group)
+	require.Len(t, document.Sections, 3)
+	require.Equal(t, supervisordkratos.GenerateGroupConfig(group), document.String())
+
+	section, ok := document.Lookup("group:services")
+	require.True(t, ok)
+	value, ok := section.Lookup("programs")
+	require.True(t, ok)
+	require.Equal(t, "api,job", value)
+
+	_, ok = document.Lookup("program:none")
+	require.False(t, ok)
+}
@@ -0,0 +1,94 @@
+package supervisordkratos
+
+import (
+	"strconv"
+)
+
+// EffectiveOptions list every option supervisord applies to program, defaults included
+// Rendered values win over defaults, annotations come last as resources.* and binary.* keys
+// Values differing from constructor default carry a "default: X" comment
+//
+// EffectiveOptions 列出 supervisord 应用于程序的所有选项，包括默认值
+// 渲染值优先于默认值，注解以 resources.* 和 binary.* 键放在最后
+// 与构造默认值不同的值带有 "default: X" 注释
+func EffectiveOptions(program *ProgramConfig) []*Entry {
+	section := NewProgramSection(program)
+
+	// Defaults mirror supervisord defaults, see NewProgramConfig
+	// killasgroup left unset follows stopasgroup, as supervisord reads it
+	// 默认值与 supervisord 默认值一致，参见 NewProgramConfig
+	// 未设置的 killasgroup 跟随 stopasgroup，与 supervisord 读取方式一致
+	results := []*Entry{
+		{Key: "user", Value: program.UserName},
+		{Key: "directory", Value: program.Root},
+		{Key: "command", Value: program.commandLine()},
+		{Key: "environment", Value: combineSsMap(program.Environment.Get(), ",")},
+		{Key: "autostart", Value: strconv.FormatBool(program.AutoStart.Get())},
+		{Key: "autorestart", Value: formatAutoRestart(program.AutoRestart.Get())},
+		{Key: "startretries", Value: strconv.Itoa(program.StartRetries.Get())},
+		{Key: "startsecs", Value: strconv.Itoa(program.StartSecs.Get())},
+		{Key: "stdout_logfile", Value: program.stdoutLogfile()},
+		{Key: "stdout_logfile_maxbytes", Value: program.LogMaxBytes.Get()},
+		{Key: "stdout_logfile_backups", Value: strconv.Itoa(program.LogBackups.Get())},
+		{Key: "stderr_logfile", Value: program.stderrLogfile()},
+		{Key: "stderr_logfile_maxbytes", Value: program.LogMaxBytes.Get()},
+		{Key: "stderr_logfile_backups", Value: strconv.Itoa(program.LogBackups.Get())},
+		{Key: "redirect_stderr", Value: strconv.FormatBool(program.RedirectStderr.Get())},
+		{Key: "stopasgroup", Value: strconv.FormatBool(program.StopAsGroup.Get())},
+		{Key: "stopwaitsecs", Value: strconv.Itoa(program.StopWaitSecs.Get())},
+		{Key: "killasgroup", Value: strconv.FormatBool(program.KillAsGroup.Get() || (!program.KillAsGroup.IsSet() && program.StopAsGroup.Get()))},
+		{Key: "stopsignal", Value: program.StopSignal.Get()},
+		{Key: "priority", Value: strconv.Itoa(program.Priority.Get())},
+		{Key: "exitcodes", Value: combineInts(program.ExitCodes.Get(), ",")},
+		{Key: "numprocs", Value: strconv.Itoa(program.NumProcs.Get())},
+		{Key: "process_name", Value: program.ProcessName.Get()},
+	}
+	defaults := optionDefaults(program)
+	for _, entry := range results {
+		if value, ok := section.Lookup(entry.Key); ok {
+			entry.Value = value
+		}
+		if value, ok := defaults[entry.Key]; ok && value != entry.Value {
+			entry.Comments = []string{"default: " + value}
+		}
+	}
+	for _, entry := range program.RawOptions {
+		results = append(results, &Entry{Key: entry.Key, Value: entry.Value})
+	}
+	if program.Resources.IsSet() {
+		resources := program.Resources.Get()
+		results = append(results,
+			&Entry{Key: "resources.cpu_millis", Value: strconv.Itoa(resources.CPUMillis)},
+			&Entry{Key: "resources.memory_bytes", Value: strconv.FormatInt(resources.MemoryBytes, 10)},
+		)
+	}
+	if program.BinaryVersion.IsSet() {
+		results = append(results, &Entry{Key: "binary.version", Value: program.BinaryVersion.Get()})
+	}
+	return results
+}
+
+// optionDefaults constructor default of each Opt backed option, rendered as supervisord text
+// optionDefaults 每个由 Opt 支持的选项的构造默认值，以 supervisord 文本形式呈现
+func optionDefaults(program *ProgramConfig) map[string]string {
+	return map[string]string{
+		"environment":             combineSsMap(program.Environment.Default(), ","),
+		"autostart":               strconv.FormatBool(program.AutoStart.Default()),
+		"autorestart":             formatAutoRestart(program.AutoRestart.Default()),
+		"startretries":            strconv.Itoa(program.StartRetries.Default()),
+		"startsecs":               strconv.Itoa(program.StartSecs.Default()),
+		"stdout_logfile_maxbytes": program.LogMaxBytes.Default(),
+		"stdout_logfile_backups":  strconv.Itoa(program.LogBackups.Default()),
+		"stderr_logfile_maxbytes": program.LogMaxBytes.Default(),
+		"stderr_logfile_backups":  strconv.Itoa(program.LogBackups.Default()),
+		"redirect_stderr":         strconv.FormatBool(program.RedirectStderr.Default()),
+		"stopasgroup":             strconv.FormatBool(program.StopAsGroup.Default()),
+		"stopwaitsecs":            strconv.Itoa(program.StopWaitSecs.Default()),
+		"killasgroup":             strconv.FormatBool(program.KillAsGroup.Default()),
+		"stopsignal":              program.StopSignal.Default(),
+		"priority":                strconv.Itoa(program.Priority.Default()),
+		"exitcodes":               combineInts(program.ExitCodes.Default(), ","),
+		"numprocs":                strconv.Itoa(program.NumProcs.Default()),
+		"process_name":            program.ProcessName.Default(),
+	}
+}
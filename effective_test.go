@@ -0,0 +1,42 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEffectiveOptions(t *testing.T) {
+	// Test effective options include defaults, rendered values and annotations
+	// 测试有效选项包含默认值、渲染值和注解
+	program := supervisordkratos.NewProgramConfig(
+		"api",
+		"/opt/api",
+		"deploy",
+		"/var/log/api",
+	).WithDisabled(true).WithStartSecs(5).WithResources(250, 128<<20)
+
+	options := make(map[string]string)
+	comments := make(map[string][]string)
+	for _, entry := range supervisordkratos.EffectiveOptions(program) {
+		options[entry.Key] = entry.Value
+		comments[entry.Key] = entry.Comments
+	}
+	t.Log(options)
+
+	require.Equal(t, "false", options["autostart"])
+	require.Equal(t, "5", options["startsecs"])
+	require.Equal(t, "3", options["startretries"])
+	require.Equal(t, "unexpected", options["autorestart"])
+	require.Equal(t, "TERM", options["stopsignal"])
+	require.Equal(t, "/var/log/api/api.err", options["stderr_logfile"])
+	require.Equal(t, "250", options["resources.cpu_millis"])
+	require.Equal(t, "134217728", options["resources.memory_bytes"])
+
+	// Overridden values name the constructor default
+	// 被覆盖的值注明构造默认值
+	require.Equal(t, []string{"default: 1"}, comments["startsecs"])
+	require.Equal(t, []string{"default: true"}, comments["autostart"])
+	require.Empty(t, comments["startretries"])
+}
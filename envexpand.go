@@ -0,0 +1,54 @@
+package supervisordkratos
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// envExpansionPattern matches %(ENV_NAME)s expressions in option values
+// envExpansionPattern 匹配选项值中的 %(ENV_NAME)s 表达式
+var envExpansionPattern = regexp.MustCompile(`%\(ENV_(\w+)\)s`)
+
+// isEnvRooted reports whether path starts with %(ENV_NAME)s, resolved by supervisord at read time
+// Such paths count as absolute, CheckEnvExpansions verifies the expanded value
+//
+// isEnvRooted 报告路径是否以 %(ENV_NAME)s 开头，由 supervisord 在读取时解析
+// 此类路径视为绝对路径，由 CheckEnvExpansions 校验展开后的值
+func isEnvRooted(path string) bool {
+	loc := envExpansionPattern.FindStringIndex(path)
+	return loc != nil && loc[0] == 0
+}
+
+// CheckEnvExpansions verifies %(ENV_NAME)s used in program Root and SlogRoot are declared
+// in [supervisord] environment, and that env-rooted paths expand to absolute paths
+// supervisord expands ENV_ names from its own process environment when reading files,
+// so the service manager running supervisord must export the same values
+//
+// CheckEnvExpansions 校验程序 Root 和 SlogRoot 中使用的 %(ENV_NAME)s 已在 [supervisord] environment 中声明，
+// 并且以环境变量开头的路径展开后为绝对路径
+// supervisord 在读取文件时从自身进程环境中展开 ENV_ 名称，
+// 因此运行 supervisord 的服务管理器必须导出相同的值
+func (c *SupervisordConfig) CheckEnvExpansions(programs ...*ProgramConfig) error {
+	environment := c.Environment.Get()
+	for _, program := range programs {
+		for _, path := range []string{program.Root, program.SlogRoot} {
+			if program.AutoLogs.Get() && path == program.SlogRoot {
+				continue
+			}
+			expanded := path
+			for _, match := range envExpansionPattern.FindAllStringSubmatch(path, -1) {
+				value, ok := environment[match[1]]
+				if !ok {
+					return errors.Errorf("program %s: %s references %s not declared in [supervisord] environment", program.Name, path, match[1])
+				}
+				expanded = strings.ReplaceAll(expanded, match[0], value)
+			}
+			if isEnvRooted(path) && !program.TargetOS.Get().IsAbs(expanded) {
+				return errors.Errorf("program %s: %s expands to %q which is not absolute", program.Name, path, expanded)
+			}
+		}
+	}
+	return nil
+}
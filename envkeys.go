@@ -0,0 +1,45 @@
+package supervisordkratos
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// envKeyPattern POSIX portable environment variable name
+// envKeyPattern POSIX 可移植的环境变量名称
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedEnvPrefix supervisord sets SUPERVISOR_* in each child, program environment would override them
+// reservedEnvPrefix supervisord 为每个子进程设置 SUPERVISOR_*，程序环境变量会覆盖它们
+const reservedEnvPrefix = "SUPERVISOR_"
+
+// WithUppercaseEnv require environment keys to follow the uppercase convention
+// WithUppercaseEnv 要求环境变量名遵循大写约定
+func (v *Validator) WithUppercaseEnv(uppercaseEnv bool) *Validator {
+	v.UppercaseEnv = uppercaseEnv
+	return v
+}
+
+// checkEnvKeys reports environment keys that supervisord would split, expand or shadow
+// Keys are rendered unquoted, so spaces, '=', ',' and '%' break the environment line
+//
+// checkEnvKeys 报告会被 supervisord 拆分、展开或遮蔽的环境变量名
+// 变量名以不带引号的形式渲染，因此空格、'='、',' 和 '%' 会破坏 environment 行
+func checkEnvKeys(program *ProgramConfig, uppercaseEnv bool) error {
+	for _, key := range slices.Sorted(maps.Keys(program.Environment.Get())) {
+		if !envKeyPattern.MatchString(key) {
+			return errors.Errorf("program %s: environment key %q is not a POSIX name", program.Name, key)
+		}
+		if strings.HasPrefix(key, reservedEnvPrefix) {
+			return errors.Errorf("program %s: environment key %q is reserved by supervisord", program.Name, key)
+		}
+		if uppercaseEnv && key != strings.ToUpper(key) {
+			return errors.Errorf("program %s: environment key %q is not uppercase", program.Name, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateEnvKeys(t *testing.T) {
+	// Test environment keys supervisord would mangle fail build
+	// 测试会被 supervisord 破坏的环境变量名导致构建失败
+	for _, key := range []string{"APP ENV", "1PORT", "A=B", "A,B", "HOME%", "", "SUPERVISOR_ENABLED"} {
+		program := supervisordkratos.NewProgramConfig(
+			"api",
+			"/opt/api",
+			"deploy",
+			"/var/log/api",
+		).WithEnvironment(map[string]string{key: "1"})
+
+		_, err := supervisordkratos.BuildProgramConfig(program)
+		require.ErrorContains(t, err, "environment key", key)
+	}
+}
+
+func TestValidateUppercaseEnv(t *testing.T) {
+	// Test uppercase convention is an opt-in policy
+	// 测试大写约定是可选策略
+	program := supervisordkratos.NewProgramConfig(
+		"api",
+		"/opt/api",
+		"deploy",
+		"/var/log/api",
+	).WithEnvironment(map[string]string{"APP_ENV": "production", "http_proxy": "http://proxy:3128"})
+
+	require.NoError(t, supervisordkratos.ValidateProgramConfig(program))
+
+	validator := supervisordkratos.NewValidator().WithUppercaseEnv(true)
+	require.ErrorContains(t, validator.ValidateProgram(program), `"http_proxy" is not uppercase`)
+}
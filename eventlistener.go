@@ -0,0 +1,109 @@
+package supervisordkratos
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/yyle88/must"
+)
+
+// Event one notification received by an eventlistener
+// Headers come from the protocol header line, Payload is the raw event body
+//
+// Event eventlistener 收到的单个通知
+// Headers 来自协议头行，Payload 是原始事件内容
+type Event struct {
+	Headers map[string]string // Header tokens like ver, serial, eventname, len // 头部字段，例如 ver、serial、eventname、len
+	Payload string            // Event body of len bytes // 长度为 len 字节的事件内容
+}
+
+// EventName returns eventname header, e.g. PROCESS_STATE_RUNNING
+// EventName 返回 eventname 头部，例如 PROCESS_STATE_RUNNING
+func (e *Event) EventName() string {
+	return e.Headers["eventname"]
+}
+
+// EventHandler handles one event, returning error makes supervisord buffer and resend it
+// EventHandler 处理单个事件，返回错误会让 supervisord 缓存并重新发送该事件
+type EventHandler func(event *Event) error
+
+// RunEventListener speak supervisord eventlistener protocol on stdin/stdout until stdin closes
+// Writes READY, reads header line and payload, calls handler, then answers RESULT OK or FAIL
+// Nothing else may be written to stdout, log to stderr instead
+//
+// RunEventListener 在 stdin/stdout 上运行 supervisord eventlistener 协议，直到 stdin 关闭
+// 写入 READY，读取头行和内容，调用 handler，然后回复 RESULT OK 或 FAIL
+// stdout 上不能写入其他内容，日志请写到 stderr
+func RunEventListener(stdin io.Reader, stdout io.Writer, handler EventHandler) error {
+	must.True(handler != nil)
+	reader := bufio.NewReader(stdin)
+	for {
+		if _, err := io.WriteString(stdout, "READY\n"); err != nil {
+			return errors.WithStack(err)
+		}
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) && line == "" {
+				return nil
+			}
+			return errors.WithStack(err)
+		}
+		event, err := readEvent(reader, line)
+		if err != nil {
+			return err
+		}
+		result := "OK"
+		if err := handler(event); err != nil {
+			result = "FAIL"
+		}
+		if _, err := io.WriteString(stdout, "RESULT "+strconv.Itoa(len(result))+"\n"+result); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+}
+
+// readEvent parse header line tokens and read len bytes of payload
+// readEvent 解析头行字段并读取 len 字节的内容
+func readEvent(reader *bufio.Reader, line string) (*Event, error) {
+	headers := parseTokens(line)
+	size, err := strconv.Atoi(headers["len"])
+	if err != nil {
+		return nil, errors.Errorf("invalid event header %q", strings.TrimSpace(line))
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &Event{Headers: headers, Payload: string(payload)}, nil
+}
+
+// parseTokens split space-separated key:value tokens into map
+// parseTokens 将空格分隔的 key:value 字段拆分为映射
+func parseTokens(text string) map[string]string {
+	results := make(map[string]string)
+	for _, token := range strings.Fields(text) {
+		if key, value, ok := strings.Cut(token, ":"); ok {
+			results[key] = value
+		}
+	}
+	return results
+}
+
+// NewEventListenerSection build [eventlistener:x] Section running program as listener of events
+// Same options as [program:x], stdout carries the protocol so only stderr is worth reading
+// redirect_stderr is dropped, supervisord refuses it in listeners since it would mix into the protocol
+//
+// NewEventListenerSection 构建 [eventlistener:x] 段落，将程序作为 events 的监听器运行
+// 选项与 [program:x] 相同，stdout 用于协议通信，因此只有 stderr 值得查看
+// redirect_stderr 会被去掉，它会混入协议通信，supervisord 在监听器中拒绝该选项
+func NewEventListenerSection(program *ProgramConfig, events ...string) *Section {
+	must.Have(events)
+	section := NewProgramSection(program)
+	section.Name = "eventlistener:" + program.Name
+	section.Remove("redirect_stderr")
+	section.Set("events", strings.Join(events, ","))
+	return section
+}
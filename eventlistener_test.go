@@ -0,0 +1,72 @@
+package supervisordkratos_test
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunEventListener(t *testing.T) {
+	// Test READY/RESULT exchange with OK and FAIL answers
+	// 测试包含 OK 和 FAIL 应答的 READY/RESULT 交互
+	payload1 := "processname:api groupname:payments from_state:STARTING pid:1234"
+	payload2 := "when:1700000000"
+	stdin := strings.NewReader(
+		"ver:3.0 server:supervisor serial:1 pool:listener poolserial:1 eventname:PROCESS_STATE_RUNNING len:" + strconv.Itoa(len(payload1)) + "\n" + payload1 +
+			"ver:3.0 server:supervisor serial:2 pool:listener poolserial:2 eventname:TICK_60 len:" + strconv.Itoa(len(payload2)) + "\n" + payload2,
+	)
+	var stdout bytes.Buffer
+	var names []string
+	err := supervisordkratos.RunEventListener(stdin, &stdout, func(event *supervisordkratos.Event) error {
+		names = append(names, event.EventName())
+		if event.EventName() == "TICK_60" {
+			return errors.New("not handled")
+		}
+		require.Equal(t, payload1, event.Payload)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"PROCESS_STATE_RUNNING", "TICK_60"}, names)
+	require.Equal(t, "READY\nRESULT 2\nOKREADY\nRESULT 4\nFAILREADY\n", stdout.String())
+}
+
+func TestNewEventListenerSection(t *testing.T) {
+	// Test program rendered as eventlistener section
+	// 测试程序渲染为 eventlistener 段落
+	program := supervisordkratos.NewProgramConfig("deregister", "/opt/deregister", "deploy", "/var/log/deregister")
+
+	content := supervisordkratos.NewEventListenerSection(program, "PROCESS_STATE_STOPPING", "PROCESS_STATE_EXITED").String()
+	t.Log(content)
+
+	const expected = `[eventlistener:deregister]
+user            = deploy
+directory       = /opt/deregister
+command         = /opt/deregister/bin/deregister
+stdout_logfile  = /var/log/deregister/deregister.log
+stderr_logfile  = /var/log/deregister/deregister.err
+events          = PROCESS_STATE_STOPPING,PROCESS_STATE_EXITED
+`
+	require.Equal(t, expected, content)
+	require.NoError(t, supervisordkratos.Simulate(content))
+}
+
+func TestNewEventListenerSectionRedirectStderr(t *testing.T) {
+	// Test redirect_stderr from archetype is dropped, supervisord refuses it in listeners
+	// 测试来自形态的 redirect_stderr 被去掉，supervisord 在监听器中拒绝该选项
+	program := supervisordkratos.NewFromArchetype("deregister", supervisordkratos.ArchetypeCronWorker, "/opt/deregister", "deploy", "/var/log/deregister")
+	require.True(t, program.RedirectStderr.Get())
+
+	content := supervisordkratos.NewEventListenerSection(program, "PROCESS_STATE_STOPPING").String()
+	t.Log(content)
+	require.NotContains(t, content, "redirect_stderr")
+	require.NoError(t, supervisordkratos.Simulate(content))
+
+	// Hand-written listener keeping it is rejected
+	// 保留该选项的手写监听器会被拒绝
+	require.ErrorContains(t, supervisordkratos.Simulate(content+"redirect_stderr = true\n"), "redirect_stderr=true is not allowed")
+}
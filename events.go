@@ -0,0 +1,94 @@
+package supervisordkratos
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ProcessStateEvent typed PROCESS_STATE_* event payload
+// Fields absent from an event keep zero values, e.g. PID before RUNNING
+//
+// ProcessStateEvent 类型化的 PROCESS_STATE_* 事件内容
+// 事件中不存在的字段保持零值，例如 RUNNING 之前的 PID
+type ProcessStateEvent struct {
+	ProcessName string       // Process name, e.g. api_00 // 进程名称，例如 api_00
+	GroupName   string       // Group name // 组名称
+	FromState   ProcessState // State before transition // 转换前的状态
+	ToState     ProcessState // State after transition, from eventname // 转换后的状态，来自 eventname
+	PID         int          // Process ID in RUNNING/STOPPING/EXITED // RUNNING/STOPPING/EXITED 中的进程 ID
+	Tries       int          // Start attempts in STARTING/BACKOFF/FATAL // STARTING/BACKOFF/FATAL 中的启动尝试次数
+	Expected    bool         // Exit code was expected in EXITED // EXITED 中退出码是否符合预期
+}
+
+// FullName returns group:process name used by supervisorctl
+// FullName 返回 supervisorctl 使用的 group:process 名称
+func (e *ProcessStateEvent) FullName() string {
+	return e.GroupName + ":" + e.ProcessName
+}
+
+// TickEvent typed TICK_5/TICK_60/TICK_3600 event payload
+// TickEvent 类型化的 TICK_5/TICK_60/TICK_3600 事件内容
+type TickEvent struct {
+	Period time.Duration // Tick period from eventname // 来自 eventname 的时钟周期
+	When   time.Time     // Tick time // 时钟时间
+}
+
+// ProcessState parse PROCESS_STATE_* event into typed struct
+// ProcessState 将 PROCESS_STATE_* 事件解析为类型化结构
+func (e *Event) ProcessState() (*ProcessStateEvent, error) {
+	name, ok := strings.CutPrefix(e.EventName(), "PROCESS_STATE_")
+	if !ok {
+		return nil, errors.Errorf("event %s is not a PROCESS_STATE event", e.EventName())
+	}
+	toState, err := ParseProcessState(name)
+	if err != nil {
+		return nil, err
+	}
+	// Only the first line holds tokens, PROCESS_STATE events carry no data after it
+	// 只有第一行包含字段，PROCESS_STATE 事件之后没有数据
+	line, _, _ := strings.Cut(e.Payload, "\n")
+	tokens := parseTokens(line)
+	fromState, err := ParseProcessState(tokens["from_state"])
+	if err != nil {
+		return nil, err
+	}
+	event := &ProcessStateEvent{
+		ProcessName: tokens["processname"],
+		GroupName:   tokens["groupname"],
+		FromState:   fromState,
+		ToState:     toState,
+		Expected:    tokens["expected"] == "1",
+	}
+	for key, target := range map[string]*int{"pid": &event.PID, "tries": &event.Tries} {
+		if text, ok := tokens[key]; ok {
+			if *target, err = strconv.Atoi(text); err != nil {
+				return nil, errors.Errorf("event %s: invalid %s %q", e.EventName(), key, text)
+			}
+		}
+	}
+	return event, nil
+}
+
+// Tick parse TICK_* event into typed struct
+// Tick 将 TICK_* 事件解析为类型化结构
+func (e *Event) Tick() (*TickEvent, error) {
+	name, ok := strings.CutPrefix(e.EventName(), "TICK_")
+	if !ok {
+		return nil, errors.Errorf("event %s is not a TICK event", e.EventName())
+	}
+	seconds, err := strconv.Atoi(name)
+	if err != nil {
+		return nil, errors.Errorf("event %s: invalid tick period", e.EventName())
+	}
+	when, err := strconv.ParseInt(parseTokens(e.Payload)["when"], 10, 64)
+	if err != nil {
+		return nil, errors.Errorf("event %s: invalid when", e.EventName())
+	}
+	return &TickEvent{
+		Period: time.Duration(seconds) * time.Second,
+		When:   time.Unix(when, 0),
+	}, nil
+}
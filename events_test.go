@@ -0,0 +1,46 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventProcessState(t *testing.T) {
+	// Test PROCESS_STATE payload parsed into typed struct
+	// 测试 PROCESS_STATE 内容解析为类型化结构
+	event := &supervisordkratos.Event{
+		Headers: map[string]string{"eventname": "PROCESS_STATE_EXITED"},
+		Payload: "processname:api_00 groupname:payments from_state:RUNNING expected:0 pid:2766",
+	}
+	state, err := event.ProcessState()
+	require.NoError(t, err)
+	require.Equal(t, &supervisordkratos.ProcessStateEvent{
+		ProcessName: "api_00",
+		GroupName:   "payments",
+		FromState:   supervisordkratos.ProcessRunning,
+		ToState:     supervisordkratos.ProcessExited,
+		PID:         2766,
+		Expected:    false,
+	}, state)
+	require.Equal(t, "payments:api_00", state.FullName())
+
+	event.Headers["eventname"] = "TICK_60"
+	_, err = event.ProcessState()
+	require.Error(t, err)
+}
+
+func TestEventTick(t *testing.T) {
+	// Test TICK payload parsed into period and time
+	// 测试 TICK 内容解析为周期和时间
+	event := &supervisordkratos.Event{
+		Headers: map[string]string{"eventname": "TICK_60"},
+		Payload: "when:1201063880",
+	}
+	tick, err := event.Tick()
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, tick.Period)
+	require.Equal(t, int64(1201063880), tick.When.Unix())
+}
@@ -0,0 +1,140 @@
+package supervisordkratos
+
+import (
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ExplainSource why an emitted option is present
+// ExplainSource 输出选项存在的原因
+type ExplainSource string
+
+const (
+	SourceRequired  ExplainSource = "required"  // NewProgramConfig argument // NewProgramConfig 参数
+	SourceDerived   ExplainSource = "derived"   // Computed from Root, SlogRoot and Name // 由 Root、SlogRoot 和 Name 计算得出
+	SourceExplicit  ExplainSource = "explicit"  // Set with a With* call or by parsing // 通过 With* 调用或解析设置
+	SourceArchetype ExplainSource = "archetype" // Curated default of applied archetype // 已应用形态的预设默认值
+	SourcePolicy    ExplainSource = "policy"    // Forced by disabled, maintenance, sandbox or schedule // 由停放、维护、沙箱或定时强制
+	SourceRaw       ExplainSource = "raw"       // Unknown option kept by lenient parsing // 宽松解析保留的未知选项
+)
+
+// Explanation one emitted option with the reason it is present
+// Explanation 单个输出选项及其存在原因
+type Explanation struct {
+	Key     string        // Option name // 选项名称
+	Value   string        // Emitted value // 输出值
+	Source  ExplainSource // Reason kind // 原因类型
+	Detail  string        // Human readable reason // 可读的原因说明
+	Default string        // Constructor default, blank when option has none // 构造默认值，选项没有默认值时为空
+}
+
+// explainMethods With* method setting each explicit option
+// explainMethods 设置每个显式选项的 With* 方法
+var explainMethods = map[string]string{
+	"environment":             "WithEnvironment",
+	"autostart":               "WithAutoStart",
+	"autorestart":             "WithAutoRestart",
+	"startretries":            "WithStartRetries",
+	"startsecs":               "WithStartSecs",
+	"stdout_logfile_maxbytes": "WithLogMaxBytes",
+	"stdout_logfile_backups":  "WithLogBackups",
+	"stderr_logfile_maxbytes": "WithLogMaxBytes",
+	"stderr_logfile_backups":  "WithLogBackups",
+	"redirect_stderr":         "WithRedirectStderr",
+	"stopasgroup":             "WithStopAsGroup",
+	"stopwaitsecs":            "WithStopWaitSecs",
+	"killasgroup":             "WithKillAsGroup",
+	"stopsignal":              "WithStopSignal",
+	"priority":                "WithPriority",
+	"exitcodes":               "WithExitCodes",
+	"numprocs":                "WithNumProcs",
+	"process_name":            "WithProcessName",
+}
+
+// ExplainProgram list every emitted option of program with why it is present
+// ExplainProgram 列出程序的每个输出选项及其存在原因
+func ExplainProgram(program *ProgramConfig) []*Explanation {
+	section := NewProgramSection(program)
+
+	// Archetype values are recognized by rendering a fresh program with the same archetype
+	// 通过渲染使用相同形态的新程序来识别形态值
+	var archetype *Section
+	if program.Archetype.IsSet() {
+		archetype = NewProgramSection(newProgramConfig(program.Name, program.Root, program.UserName, program.SlogRoot).WithAutoLogs(program.AutoLogs.Get()).WithArchetype(program.Archetype.Get()))
+	}
+	rawCount := len(program.RawOptions)
+	defaults := optionDefaults(program)
+
+	results := make([]*Explanation, 0, len(section.Entries))
+	for idx, entry := range section.Entries {
+		explanation := &Explanation{Key: entry.Key, Value: entry.Value}
+		if idx < len(section.Entries)-rawCount {
+			explanation.Default = defaults[entry.Key]
+		}
+		switch {
+		case idx >= len(section.Entries)-rawCount:
+			explanation.Source, explanation.Detail = SourceRaw, "kept as-is by lenient parsing"
+		case entry.Key == "user":
+			explanation.Source, explanation.Detail = SourceRequired, "NewProgramConfig userName"
+		case entry.Key == "directory":
+			explanation.Source, explanation.Detail = SourceRequired, "NewProgramConfig root"
+		case entry.Key == "command":
+			explanation.Source, explanation.Detail = explainCommand(program)
+		case entry.Key == "stdout_logfile" || entry.Key == "stderr_logfile":
+			explanation.Source, explanation.Detail = SourceDerived, "slog root joined with program name"
+		case entry.Key == "autostart" && program.Disabled.Get():
+			explanation.Source, explanation.Detail = SourcePolicy, "disabled forces autostart=false"
+		case archetype != nil && slices.ContainsFunc(archetype.Entries, func(e *Entry) bool { return e.Key == entry.Key && e.Value == entry.Value }):
+			explanation.Source, explanation.Detail = SourceArchetype, "default of "+string(program.Archetype.Get())
+		default:
+			explanation.Source, explanation.Detail = SourceExplicit, "set by "+explainMethods[entry.Key]
+		}
+		results = append(results, explanation)
+	}
+	return results
+}
+
+// explainCommand reason of command line, wrappers first
+// explainCommand 命令行的原因，优先说明包装
+func explainCommand(program *ProgramConfig) (ExplainSource, string) {
+	switch {
+	case program.Maintenance.Get() != "":
+		return SourcePolicy, "maintenance placeholder replaces the binary"
+	case program.CPUAffinity.Get() != "":
+		return SourcePolicy, "taskset pins the command to cpus " + program.CPUAffinity.Get()
+	case program.Schedule.IsSet():
+		return SourcePolicy, "schedule loop runs the binary every " + program.Schedule.Get().Round(time.Second).String()
+	case program.needsWrapper():
+		return SourcePolicy, "wrapper script runs the binary, see GenerateWrapperScript"
+	case program.Sandbox.IsSet():
+		return SourcePolicy, "sandbox " + string(program.Sandbox.Get().Tool) + " wraps the binary"
+	default:
+		return SourceDerived, "root/bin/name"
+	}
+}
+
+// Explain render program section with a comment above each option telling why it is present
+// Values overriding a non-blank constructor default also name that default
+// Output stays valid supervisord INI, handy when debugging unexpected values on a host
+//
+// Explain 渲染程序段落，并在每个选项上方添加说明其存在原因的注释
+// 覆盖非空构造默认值的值同时注明该默认值
+// 输出仍然是有效的 supervisord INI，便于排查主机上的意外值
+func Explain(program *ProgramConfig) string {
+	section := NewProgramSection(program)
+	explanations := ExplainProgram(program)
+	if len(explanations) != len(section.Entries) {
+		panic(errors.New("IMPOSSIBLE: EXPLANATIONS MISMATCH ENTRIES"))
+	}
+	for idx, entry := range section.Entries {
+		explanation := explanations[idx]
+		comment := string(explanation.Source) + ": " + explanation.Detail
+		if explanation.Default != "" && explanation.Default != explanation.Value {
+			comment += " (constructor default " + explanation.Default + ")"
+		}
+		entry.Comments = append(entry.Comments, comment)
+	}
+	return section.String()
+}
@@ -0,0 +1,59 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	// Test each emitted option carries its source
+	// 测试每个输出选项都带有其来源
+	program := supervisordkratos.NewFromArchetype("orders", supervisordkratos.ArchetypeGRPCService, "/opt/orders", "deploy", "/var/log/orders").
+		WithStopWaitSecs(45).
+		WithDisabled(true)
+
+	content := supervisordkratos.Explain(program)
+	t.Log(content)
+
+	const expected = `; DISABLED: parked by supervisordkratos, autostart forced to false
+[program:orders]
+; required: NewProgramConfig userName
+user            = deploy
+; required: NewProgramConfig root
+directory       = /opt/orders
+; derived: root/bin/name
+command         = /opt/orders/bin/orders
+; policy: disabled forces autostart=false (constructor default true)
+autostart       = false
+; archetype: default of grpc-service (constructor default unexpected)
+autorestart     = true
+; archetype: default of grpc-service (constructor default 1)
+startsecs       = 3
+; derived: slog root joined with program name
+stdout_logfile  = /var/log/orders/orders.log
+; derived: slog root joined with program name
+stderr_logfile  = /var/log/orders/orders.err
+; archetype: default of grpc-service (constructor default false)
+stopasgroup     = true
+; explicit: set by WithStopWaitSecs (constructor default 10)
+stopwaitsecs    = 45
+; archetype: default of grpc-service (constructor default false)
+killasgroup     = true
+; archetype: default of grpc-service (constructor default 999)
+priority        = 500
+`
+	require.Equal(t, expected, content)
+
+	// Explanations carry constructor defaults of Opt backed options
+	// 说明中带有由 Opt 支持的选项的构造默认值
+	explanations := supervisordkratos.ExplainProgram(program)
+	require.Equal(t, "stopwaitsecs", explanations[9].Key)
+	require.Equal(t, "10", explanations[9].Default)
+	require.Equal(t, "", explanations[0].Default)
+
+	// Explained output is still valid supervisord config
+	// 带说明的输出仍然是有效的 supervisord 配置
+	require.NoError(t, supervisordkratos.Simulate(content))
+}
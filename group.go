@@ -1,28 +1,52 @@
 package supervisordkratos
 
 import (
+	"maps"
+	"slices"
 	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/yyle88/must"
-	"github.com/yyle88/printgo"
 )
 
 // GroupConfig supervisord group configuration
 // supervisord 组配置
 type GroupConfig struct {
-	Name     string           // Group name // 组名称
-	Programs []*ProgramConfig // Program configs // 程序配置列表
+	Name            string            // Group name // 组名称
+	Programs        []*ProgramConfig  // Program configs // 程序配置列表
+	Standalone      []*ProgramConfig  // Programs rendered in same file but not in programs= line // 在同一文件中渲染但不在 programs= 中的程序
+	ExcludeDisabled bool              // Leave disabled programs out of programs= line // 将停放的程序排除在 programs= 之外
+	DefaultUser     string            // Account replacing program users when set // 设置后替换程序用户的账户
+	UserOverrides   map[string]string // Program name to account, wins over DefaultUser // 程序名称到账户的映射，优先于 DefaultUser
 }
 
 // NewGroupConfig create new GroupConfig
 // 创建新的 GroupConfig
 func NewGroupConfig(name string) *GroupConfig {
 	return &GroupConfig{
-		Name:     must.Nice(name),
-		Programs: make([]*ProgramConfig, 0),
+		Name:            must.Nice(name),
+		Programs:        make([]*ProgramConfig, 0),
+		Standalone:      make([]*ProgramConfig, 0),
+		ExcludeDisabled: false,
+		DefaultUser:     "",
+		UserOverrides:   make(map[string]string),
 	}
 }
 
+// NewGroupFromMap create group of uniform programs from service name to root map
+// Programs share user and slog root, and are added in name order for stable output
+//
+// NewGroupFromMap 根据服务名称到根目录的映射创建统一的程序组
+// 程序共享用户和日志根目录，按名称顺序添加以保证输出稳定
+func NewGroupFromMap(group string, services map[string]string, userName string, slogRoot string) *GroupConfig {
+	must.True(len(services) > 0)
+	config := NewGroupConfig(group)
+	for _, name := range slices.Sorted(maps.Keys(services)) {
+		config.AddProgram(NewProgramConfig(name, services[name], userName, slogRoot))
+	}
+	return config
+}
+
 // AddProgram add program to group
 // 添加程序到组
 func (g *GroupConfig) AddProgram(program *ProgramConfig) *GroupConfig {
@@ -30,6 +54,70 @@ func (g *GroupConfig) AddProgram(program *ProgramConfig) *GroupConfig {
 	return g
 }
 
+// AddStandaloneProgram add program rendered in group file but left out of programs= line
+// Useful with utility processes that should not restart with the group
+//
+// AddStandaloneProgram 添加在组文件中渲染但不在 programs= 中的程序
+// 适用于不应随组一起重启的辅助进程
+func (g *GroupConfig) AddStandaloneProgram(program *ProgramConfig) *GroupConfig {
+	g.Standalone = append(g.Standalone, program)
+	return g
+}
+
+// WithExcludeDisabled set whether disabled programs stay out of programs= line
+// Their [program:x] sections are still rendered
+//
+// WithExcludeDisabled 设置停放的程序是否排除在 programs= 之外
+// 它们的 [program:x] 段落仍然会被渲染
+func (g *GroupConfig) WithExcludeDisabled(excludeDisabled bool) *GroupConfig {
+	g.ExcludeDisabled = excludeDisabled
+	return g
+}
+
+// WithDefaultUser set account used by every program in group, unless overridden
+// WithDefaultUser 设置组内每个程序使用的账户，除非被单独覆盖
+func (g *GroupConfig) WithDefaultUser(userName string) *GroupConfig {
+	g.DefaultUser = must.Nice(userName)
+	return g
+}
+
+// WithUserOverride set account of one program, winning over DefaultUser
+// WithUserOverride 设置单个程序的账户，优先于 DefaultUser
+func (g *GroupConfig) WithUserOverride(programName string, userName string) *GroupConfig {
+	g.UserOverrides[must.Nice(programName)] = must.Nice(userName)
+	return g
+}
+
+// UserOf resolve program account: override, then group default, then program UserName
+// UserOf 解析程序账户：先单独覆盖，再组默认值，最后程序 UserName
+func (g *GroupConfig) UserOf(program *ProgramConfig) string {
+	if userName, ok := g.UserOverrides[program.Name]; ok {
+		return userName
+	}
+	if g.DefaultUser != "" {
+		return g.DefaultUser
+	}
+	return program.UserName
+}
+
+// resolvedPrograms returns programs with accounts resolved by UserOf
+// Programs needing another account are shallow copies, the originals stay untouched
+//
+// resolvedPrograms 返回按 UserOf 解析账户后的程序
+// 需要更换账户的程序为浅拷贝，原程序保持不变
+func (g *GroupConfig) resolvedPrograms(programs []*ProgramConfig) []*ProgramConfig {
+	results := make([]*ProgramConfig, 0, len(programs))
+	for _, program := range programs {
+		if userName := g.UserOf(program); userName != program.UserName {
+			copied := *program
+			copied.UserName = userName
+			program = &copied
+		}
+		results = append(results, program)
+	}
+	return results
+}
+
 // GenerateGroupConfig generate supervisord group configuration in INI format
 // Creates complete group config with name section and programs
 // Outputs group section then program sections with spacing
@@ -38,29 +126,120 @@ func (g *GroupConfig) AddProgram(program *ProgramConfig) *GroupConfig {
 // 创建包含名称段和程序的完整组配置
 // 输出组段落然后输出程序段落，使用间距
 func GenerateGroupConfig(group *GroupConfig) string {
+	return NewGroupDocument(group).String()
+}
+
+// GenerateGroupHeader generate only the [group:x] section, member program sections are left out
+// For grouping files whose program files are owned by other teams
+// Groups holding only standalone programs have no header, blank is returned
+//
+// GenerateGroupHeader 只生成 [group:x] 段落，不输出成员程序段落
+// 适用于程序文件由其他团队维护、只管理分组文件的场景
+// 只包含独立程序的组没有段头，返回空字符串
+func GenerateGroupHeader(group *GroupConfig) string {
+	if len(group.Programs) == 0 {
+		return ""
+	}
+	return NewGroupDocument(group).Sections[0].String()
+}
+
+// GenerateGroupsConfig generate several groups into one file, sharing program sections
+// Each [program:x] is emitted once even when listed in many groups
+//
+// GenerateGroupsConfig 将多个组生成到一个文件中，共享程序段落
+// 即使程序出现在多个组中，每个 [program:x] 也只输出一次
+func GenerateGroupsConfig(groups ...*GroupConfig) (string, error) {
+	document, err := NewGroupsDocument(groups...)
+	if err != nil {
+		return "", err
+	}
+	return document.String(), nil
+}
+
+// NewGroupsDocument build Document with all [group:x] sections then deduplicated program sections
+// Programs sharing a name must render identically, otherwise returns error
+//
+// NewGroupsDocument 构建包含所有 [group:x] 段落和去重程序段落的 Document
+// 同名程序必须渲染结果一致，否则返回错误
+func NewGroupsDocument(groups ...*GroupConfig) (*Document, error) {
+	must.Have(groups)
+
+	document := NewDocument()
+	programs := make([]*Section, 0)
+	programOwners := make(map[string]string)
+	for _, group := range groups {
+		sections := NewGroupDocument(group).Sections
+		if len(group.Programs) > 0 {
+			document.Add(sections[0])
+			sections = sections[1:]
+		}
+		for _, section := range sections {
+			if owner, exists := programOwners[section.Name]; exists {
+				idx := slices.IndexFunc(programs, func(s *Section) bool { return s.Name == section.Name })
+				if programs[idx].String() != section.String() {
+					return nil, errors.Errorf("group %s: %s differs from the one in group %s", group.Name, section.Name, owner)
+				}
+				continue
+			}
+			programOwners[section.Name] = group.Name
+			programs = append(programs, section)
+		}
+	}
+	// Group headers sit together, spacing them like single group files
+	// 组段头放在一起，间距与单组文件一致
+	for _, section := range document.Sections {
+		section.Padding = 0
+	}
+	if len(document.Sections) > 0 {
+		document.Sections[len(document.Sections)-1].Padding = 1
+	}
+	return document.Add(programs...), nil
+}
+
+// NewGroupDocument build Document with [group:x] section then program sections
+// Group section keeps one extra blank line before the first program
+// Groups holding only standalone programs render their program sections without [group:x]
+//
+// NewGroupDocument 构建包含 [group:x] 段落和程序段落的 Document
+// 组段落在第一个程序前保留一个额外空行
+// 只包含独立程序的组只渲染程序段落，不输出 [group:x]
+func NewGroupDocument(group *GroupConfig) *Document {
 	must.Full(group)
 	must.Nice(group.Name)
-	must.Have(group.Programs)
+	must.True(len(group.Programs)+len(group.Standalone) > 0)
 
-	ptx := printgo.NewPTX()
+	document := NewDocument()
+	if len(group.Programs) > 0 {
+		document.Add(newGroupSection(group))
+	}
+	// Generate each program section
+	// 生成每个程序段落
+	for _, program := range group.resolvedPrograms(group.Programs) {
+		document.Add(NewProgramSection(program))
+	}
+	// Standalone programs come after group members
+	// 独立程序放在组成员之后
+	for _, program := range group.resolvedPrograms(group.Standalone) {
+		document.Add(NewProgramSection(program))
+	}
+	return document
+}
 
+// newGroupSection build [group:x] section listing member programs
+// newGroupSection 构建列出成员程序的 [group:x] 段落
+func newGroupSection(group *GroupConfig) *Section {
 	// Generate group name section
 	// 生成组名称段
-	ptx.Println(`[group:` + group.Name + `]`)
 	programs := make([]string, 0, len(group.Programs))
 	for _, p := range group.Programs {
+		if group.ExcludeDisabled && p.Disabled.Get() {
+			continue
+		}
 		programs = append(programs, p.Name)
 	}
-	ptx.Println(`programs=` + strings.Join(programs, ","))
-	ptx.Println()
-
-	// Generate each program config
-	// 生成每个程序配置
-	for _, program := range group.Programs {
-		ptx.Println()
-		cfs := GenerateProgramConfig(program)
-		ptx.Println(strings.TrimSpace(cfs))
-	}
-
-	return ptx.String()
+	section := NewSection("group:" + group.Name)
+	section.Add("programs", strings.Join(programs, ","))
+	section.Compact = true
+	section.Padding = 1
+	return section
 }
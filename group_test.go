@@ -202,3 +202,264 @@ stderr_logfile_maxbytes = 200MB
 
 	require.Equal(t, expected, content)
 }
+
+func TestGroupWithDisabledProgram(t *testing.T) {
+	// Test disabled program stays rendered, optionally left out of programs=
+	// 测试停放的程序仍然渲染，可选择排除在 programs= 之外
+	api := supervisordkratos.NewProgramConfig(
+		"api",
+		"/opt/api",
+		"deploy",
+		"/var/log/services",
+	)
+	legacy := supervisordkratos.NewProgramConfig(
+		"legacy",
+		"/opt/legacy",
+		"deploy",
+		"/var/log/services",
+	).WithAutoStart(true).WithDisabled(true)
+
+	group := supervisordkratos.NewGroupConfig("services").
+		AddProgram(api).
+		AddProgram(legacy)
+	require.Contains(t, supervisordkratos.GenerateGroupConfig(group), "programs=api,legacy\n")
+
+	content := supervisordkratos.GenerateGroupConfig(group.WithExcludeDisabled(true))
+	t.Log(content)
+
+	const expected = `[group:services]
+programs=api
+
+
+[program:api]
+user            = deploy
+directory       = /opt/api
+command         = /opt/api/bin/api
+stdout_logfile  = /var/log/services/api.log
+stderr_logfile  = /var/log/services/api.err
+
+; DISABLED: parked by supervisordkratos, autostart forced to false
+[program:legacy]
+user            = deploy
+directory       = /opt/legacy
+command         = /opt/legacy/bin/legacy
+autostart       = false
+stdout_logfile  = /var/log/services/legacy.log
+stderr_logfile  = /var/log/services/legacy.err
+`
+
+	require.Equal(t, expected, content)
+}
+
+func TestGroupWithStandaloneProgram(t *testing.T) {
+	// Test standalone program rendered in group file but not in programs=
+	// 测试独立程序在组文件中渲染但不在 programs= 中
+	api := supervisordkratos.NewProgramConfig(
+		"api",
+		"/opt/api",
+		"deploy",
+		"/var/log/services",
+	)
+	cleaner := supervisordkratos.NewProgramConfig(
+		"log-cleaner",
+		"/opt/log-cleaner",
+		"deploy",
+		"/var/log/services",
+	)
+
+	group := supervisordkratos.NewGroupConfig("services").
+		AddProgram(api).
+		AddStandaloneProgram(cleaner)
+
+	content := supervisordkratos.GenerateGroupConfig(group)
+	t.Log(content)
+
+	const expected = `[group:services]
+programs=api
+
+
+[program:api]
+user            = deploy
+directory       = /opt/api
+command         = /opt/api/bin/api
+stdout_logfile  = /var/log/services/api.log
+stderr_logfile  = /var/log/services/api.err
+
+[program:log-cleaner]
+user            = deploy
+directory       = /opt/log-cleaner
+command         = /opt/log-cleaner/bin/log-cleaner
+stdout_logfile  = /var/log/services/log-cleaner.log
+stderr_logfile  = /var/log/services/log-cleaner.err
+`
+
+	require.Equal(t, expected, content)
+	require.NoError(t, supervisordkratos.ValidateGroupConfig(group))
+}
+
+func TestGroupWithOnlyStandalonePrograms(t *testing.T) {
+	// Test group holding only standalone programs renders them without [group:x]
+	// 测试只包含独立程序的组在没有 [group:x] 的情况下渲染它们
+	cleaner := supervisordkratos.NewProgramConfig(
+		"log-cleaner",
+		"/opt/log-cleaner",
+		"deploy",
+		"/var/log/services",
+	)
+	group := supervisordkratos.NewGroupConfig("utilities").AddStandaloneProgram(cleaner)
+
+	content := supervisordkratos.GenerateGroupConfig(group)
+	t.Log(content)
+
+	const expected = `[program:log-cleaner]
+user            = deploy
+directory       = /opt/log-cleaner
+command         = /opt/log-cleaner/bin/log-cleaner
+stdout_logfile  = /var/log/services/log-cleaner.log
+stderr_logfile  = /var/log/services/log-cleaner.err
+`
+
+	require.Equal(t, expected, content)
+	require.NoError(t, supervisordkratos.ValidateGroupConfig(group))
+	require.NoError(t, supervisordkratos.Simulate(content))
+	require.Empty(t, supervisordkratos.GenerateGroupHeader(group))
+
+	combined, err := supervisordkratos.GenerateGroupsConfig(group)
+	require.NoError(t, err)
+	require.Equal(t, expected, combined)
+
+	require.Panics(t, func() {
+		supervisordkratos.GenerateGroupConfig(supervisordkratos.NewGroupConfig("empty"))
+	})
+}
+
+func TestGenerateGroupsSharedProgram(t *testing.T) {
+	// Test program shared by two groups is emitted once
+	// 测试两个组共享的程序只输出一次
+	gateway := supervisordkratos.NewProgramConfig(
+		"gateway",
+		"/opt/gateway",
+		"deploy",
+		"/var/log/services",
+	)
+	payments := supervisordkratos.NewProgramConfig(
+		"payments",
+		"/opt/payments",
+		"deploy",
+		"/var/log/services",
+	)
+
+	edge := supervisordkratos.NewGroupConfig("edge").AddProgram(gateway)
+	billing := supervisordkratos.NewGroupConfig("billing").AddProgram(gateway).AddProgram(payments)
+
+	content, err := supervisordkratos.GenerateGroupsConfig(edge, billing)
+	require.NoError(t, err)
+	t.Log(content)
+
+	const expected = `[group:edge]
+programs=gateway
+
+[group:billing]
+programs=gateway,payments
+
+
+[program:gateway]
+user            = deploy
+directory       = /opt/gateway
+command         = /opt/gateway/bin/gateway
+stdout_logfile  = /var/log/services/gateway.log
+stderr_logfile  = /var/log/services/gateway.err
+
+[program:payments]
+user            = deploy
+directory       = /opt/payments
+command         = /opt/payments/bin/payments
+stdout_logfile  = /var/log/services/payments.log
+stderr_logfile  = /var/log/services/payments.err
+`
+
+	require.Equal(t, expected, content)
+
+	// Same name with different settings is rejected
+	// 同名但设置不同时被拒绝
+	other := supervisordkratos.NewProgramConfig(
+		"gateway",
+		"/opt/gateway",
+		"deploy",
+		"/var/log/services",
+	).WithPriority(10)
+	_, err = supervisordkratos.GenerateGroupsConfig(edge, supervisordkratos.NewGroupConfig("other").AddProgram(other))
+	require.Error(t, err)
+}
+
+func TestNewGroupFromMap(t *testing.T) {
+	// Test uniform programs built from name to root map in name order
+	// 测试根据名称到根目录的映射按名称顺序构建统一程序
+	group := supervisordkratos.NewGroupFromMap("shop", map[string]string{
+		"order": "/opt/order",
+		"cart":  "/opt/cart",
+	}, "deploy", "/var/log/shop")
+
+	content := supervisordkratos.GenerateGroupConfig(group)
+	t.Log(content)
+
+	const expected = `[group:shop]
+programs=cart,order
+
+
+[program:cart]
+user            = deploy
+directory       = /opt/cart
+command         = /opt/cart/bin/cart
+stdout_logfile  = /var/log/shop/cart.log
+stderr_logfile  = /var/log/shop/cart.err
+
+[program:order]
+user            = deploy
+directory       = /opt/order
+command         = /opt/order/bin/order
+stdout_logfile  = /var/log/shop/order.log
+stderr_logfile  = /var/log/shop/order.err
+`
+	require.Equal(t, expected, content)
+}
+
+func TestGroupUserOverrides(t *testing.T) {
+	// Test group default user with per-program override
+	// 测试组默认用户及单个程序覆盖
+	api := supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/api")
+	ledger := supervisordkratos.NewProgramConfig("ledger", "/opt/ledger", "deploy", "/var/log/ledger")
+	group := supervisordkratos.NewGroupConfig("payments").
+		AddProgram(api).
+		AddProgram(ledger).
+		WithDefaultUser("svc-payments").
+		WithUserOverride("ledger", "svc-ledger")
+
+	content := supervisordkratos.GenerateGroupConfig(group)
+	t.Log(content)
+
+	require.Contains(t, content, "[program:api]\nuser            = svc-payments\n")
+	require.Contains(t, content, "[program:ledger]\nuser            = svc-ledger\n")
+	// Program configs themselves stay untouched
+	// 程序配置本身保持不变
+	require.Equal(t, "deploy", api.UserName)
+	require.Equal(t, "svc-ledger", group.UserOf(ledger))
+}
+
+func TestGenerateGroupHeader(t *testing.T) {
+	// Test header holds group section only
+	// 测试段头只包含组段落
+	group := supervisordkratos.NewGroupConfig("payments").
+		AddProgram(supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/api")).
+		AddProgram(supervisordkratos.NewProgramConfig("worker", "/opt/worker", "deploy", "/var/log/worker"))
+
+	content := supervisordkratos.GenerateGroupHeader(group)
+	t.Log(content)
+
+	const expected = `[group:payments]
+programs=api,worker
+`
+
+	require.Equal(t, expected, content)
+	require.NoError(t, supervisordkratos.Simulate(content+"\n"+supervisordkratos.GenerateProgramConfig(group.Programs[0])+"\n"+supervisordkratos.GenerateProgramConfig(group.Programs[1])))
+}
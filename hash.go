@@ -0,0 +1,28 @@
+package supervisordkratos
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// SectionHash stable hex sha256 of the emitted [program:x] section
+// Covers section name and option key/value pairs in order, comments and alignment are left out
+// Equal hashes mean supervisord sees the same program, so deploy systems can skip reread/update
+// Wrapper script content is covered too, command= only points at its fixed path
+//
+// SectionHash 输出的 [program:x] 段落的稳定十六进制 sha256
+// 覆盖段落名称及按顺序排列的选项键值对，不包含注释和对齐
+// 哈希相同表示 supervisord 看到的是相同程序，部署系统可以跳过 reread/update
+// 包装脚本内容也包含在内，command= 只指向其固定路径
+func SectionHash(program *ProgramConfig) string {
+	section := NewProgramSection(program)
+	hash := sha256.New()
+	hash.Write([]byte("[" + section.Name + "]\n"))
+	for _, entry := range section.Entries {
+		hash.Write([]byte(entry.Key + "=" + entry.Value + "\n"))
+	}
+	if program.needsWrapper() {
+		hash.Write([]byte(GenerateWrapperScript(program)))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
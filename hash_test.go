@@ -0,0 +1,35 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSectionHash(t *testing.T) {
+	// Test hash changes with options but not with comment annotations
+	// 测试哈希随选项变化，但不随注释注解变化
+	newProgram := func() *supervisordkratos.ProgramConfig {
+		return supervisordkratos.NewProgramConfig(
+			"api",
+			"/opt/api",
+			"deploy",
+			"/var/log/api",
+		)
+	}
+
+	hash := supervisordkratos.SectionHash(newProgram())
+	t.Log(hash)
+	require.Len(t, hash, 64)
+	require.Equal(t, hash, supervisordkratos.SectionHash(newProgram()))
+	require.Equal(t, hash, supervisordkratos.SectionHash(newProgram().WithResources(250, 128<<20)))
+	require.NotEqual(t, hash, supervisordkratos.SectionHash(newProgram().WithStartSecs(5)))
+
+	// Wrapper scripts at the same path hash apart when their content differs
+	// 位于相同路径的包装脚本内容不同时哈希不同
+	hook := supervisordkratos.SectionHash(newProgram().WithPreStopHook("/opt/api/bin/drain", time.Second))
+	require.Equal(t, hook, supervisordkratos.SectionHash(newProgram().WithPreStopHook("/opt/api/bin/drain", time.Second)))
+	require.NotEqual(t, hook, supervisordkratos.SectionHash(newProgram().WithPreStopHook("/opt/api/bin/drain", 2*time.Second)))
+}
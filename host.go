@@ -0,0 +1,114 @@
+package supervisordkratos
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/yyle88/must"
+)
+
+// hostCommentPrefix starts the structured placement comment above each section
+// hostCommentPrefix 每个段落上方结构化放置注释的开头
+const hostCommentPrefix = "host: "
+
+// HostConfig placement metadata of host the config is meant for, rendered as comments only
+// Keeps the intended target self-evident when files are copied around
+//
+// HostConfig 配置目标主机的放置元数据，只以注释形式渲染
+// 文件被四处复制时仍能明确其预期目标
+type HostConfig struct {
+	Label      string // Host label, e.g. web-01 // 主机标签，例如 web-01
+	Datacenter string // Datacenter, blank when unknown // 数据中心，未知时为空
+	Cluster    string // Cluster, blank when unknown // 集群，未知时为空
+	Identifier string // supervisord identifier of the host, blank when unknown // 主机的 supervisord 标识符，未知时为空
+}
+
+// NewHostConfig create new HostConfig with host label
+// 创建包含主机标签的新 HostConfig
+func NewHostConfig(label string) *HostConfig {
+	return &HostConfig{
+		Label:      must.Nice(label),
+		Datacenter: "",
+		Cluster:    "",
+		Identifier: "",
+	}
+}
+
+// WithDatacenter set datacenter
+// 设置数据中心
+func (h *HostConfig) WithDatacenter(datacenter string) *HostConfig {
+	h.Datacenter = must.Nice(datacenter)
+	return h
+}
+
+// WithCluster set cluster
+// 设置集群
+func (h *HostConfig) WithCluster(cluster string) *HostConfig {
+	h.Cluster = must.Nice(cluster)
+	return h
+}
+
+// WithIdentifier set supervisord identifier, use the one of SupervisordConfig.WithIdentifier
+// 设置 supervisord 标识符，使用 SupervisordConfig.WithIdentifier 中的值
+func (h *HostConfig) WithIdentifier(identifier string) *HostConfig {
+	h.Identifier = must.Nice(identifier)
+	return h
+}
+
+// Comment returns structured comment text, e.g. "host: label=web-01 datacenter=dc1 cluster=prod"
+// Blank fields are left out
+//
+// Comment 返回结构化注释文本，例如 "host: label=web-01 datacenter=dc1 cluster=prod"
+// 空字段不输出
+func (h *HostConfig) Comment() string {
+	pairs := []string{"label=" + h.Label}
+	if h.Datacenter != "" {
+		pairs = append(pairs, "datacenter="+h.Datacenter)
+	}
+	if h.Cluster != "" {
+		pairs = append(pairs, "cluster="+h.Cluster)
+	}
+	if h.Identifier != "" {
+		pairs = append(pairs, "identifier="+h.Identifier)
+	}
+	return hostCommentPrefix + strings.Join(pairs, " ")
+}
+
+// Stamp put host comment above each section of document, replacing earlier host comments
+// Stamp 在文档每个段落上方放置主机注释，替换之前的主机注释
+func (h *HostConfig) Stamp(document *Document) *Document {
+	for _, section := range document.Sections {
+		section.Comments = slices.DeleteFunc(section.Comments, isHostComment)
+		section.Comments = append(section.Comments, h.Comment())
+	}
+	return document
+}
+
+// VerifyPlacement checks every section of config text is stamped for this host
+// Returns error naming the first section meant for another host or lacking a stamp
+//
+// VerifyPlacement 检查配置文本的每个段落都标记为本主机
+// 返回指出第一个属于其他主机或缺少标记的段落的错误
+func (h *HostConfig) VerifyPlacement(configText string) error {
+	document, err := ParseDocument(configText)
+	if err != nil {
+		return err
+	}
+	for _, section := range document.Sections {
+		idx := slices.IndexFunc(section.Comments, isHostComment)
+		if idx < 0 {
+			return errors.Errorf("section %s: no host comment", section.Name)
+		}
+		if section.Comments[idx] != h.Comment() {
+			return errors.Errorf("section %s: placed for %q, this host is %q", section.Name, section.Comments[idx], h.Comment())
+		}
+	}
+	return nil
+}
+
+// isHostComment checks if comment line is a host placement comment
+// isHostComment 检查注释行是否为主机放置注释
+func isHostComment(comment string) bool {
+	return strings.HasPrefix(comment, hostCommentPrefix)
+}
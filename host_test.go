@@ -0,0 +1,46 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostConfigStamp(t *testing.T) {
+	// Test each section carries host placement comment
+	// 测试每个段落都带有主机放置注释
+	host := supervisordkratos.NewHostConfig("web-01").
+		WithDatacenter("us-east-1").
+		WithCluster("prod").
+		WithIdentifier("web-01-supervisor")
+
+	group := supervisordkratos.NewGroupConfig("cluster").
+		AddProgram(supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/api"))
+
+	content := host.Stamp(host.Stamp(supervisordkratos.NewGroupDocument(group))).String()
+	t.Log(content)
+
+	const expected = `; host: label=web-01 datacenter=us-east-1 cluster=prod identifier=web-01-supervisor
+[group:cluster]
+programs=api
+
+
+; host: label=web-01 datacenter=us-east-1 cluster=prod identifier=web-01-supervisor
+[program:api]
+user            = deploy
+directory       = /opt/api
+command         = /opt/api/bin/api
+stdout_logfile  = /var/log/api/api.log
+stderr_logfile  = /var/log/api/api.err
+`
+
+	require.Equal(t, expected, content)
+	require.NoError(t, host.VerifyPlacement(content))
+
+	// Copies landing on another host are spotted
+	// 落到其他主机上的副本会被发现
+	other := supervisordkratos.NewHostConfig("web-02").WithDatacenter("us-east-1").WithCluster("prod")
+	require.ErrorContains(t, other.VerifyPlacement(content), "placed for")
+	require.ErrorContains(t, host.VerifyPlacement(supervisordkratos.GenerateGroupConfig(group)), "no host comment")
+}
@@ -0,0 +1,79 @@
+package supervisordkratos
+
+import (
+	"strings"
+)
+
+// Impact blast radius of a config change once supervisorctl reread/update runs
+// Impact 运行 supervisorctl reread/update 后配置变更的影响范围
+type Impact string
+
+const (
+	// ImpactCosmetic change invisible to supervisord, e.g. annotations, comments, ordering
+	// ImpactCosmetic supervisord 不可见的变更，例如注解、注释、顺序
+	ImpactCosmetic Impact = "cosmetic"
+	// ImpactReread change applied by reread/update without restarting running processes
+	// ImpactReread 通过 reread/update 应用且不重启运行中进程的变更
+	ImpactReread Impact = "reread"
+	// ImpactRestart change that makes update stop and restart the program
+	// ImpactRestart 会让 update 停止并重启程序的变更
+	ImpactRestart Impact = "restart"
+)
+
+// rank orders impacts from weakest to strongest
+// rank 将影响从弱到强排序
+func (i Impact) rank() int {
+	switch i {
+	case ImpactCosmetic:
+		return 0
+	case ImpactReread:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// ClassifyOption returns impact of changing one EffectiveOptions key
+// supervisord restarts the whole process group on any section option change,
+// only annotations that render as comments are cosmetic
+//
+// ClassifyOption 返回修改单个 EffectiveOptions 键的影响
+// supervisord 在段落任何选项变更时都会重启整个进程组，
+// 只有渲染为注释的注解是外观变更
+func ClassifyOption(key string) Impact {
+	if strings.HasPrefix(key, "resources.") || strings.HasPrefix(key, "binary.") {
+		return ImpactCosmetic
+	}
+	return ImpactRestart
+}
+
+// Impact returns the strongest impact among the program option changes
+// Impact 返回程序选项变更中最强的影响
+func (c *ProgramChange) Impact() Impact {
+	impact := ImpactCosmetic
+	for _, option := range c.Changes {
+		if value := ClassifyOption(option.Key); value.rank() > impact.rank() {
+			impact = value
+		}
+	}
+	return impact
+}
+
+// Impacts classify each program in report by name
+// Added and removed programs only need reread/update, other programs keep running
+//
+// Impacts 按名称对报告中的每个程序进行分类
+// 新增和删除的程序只需 reread/update，其他程序保持运行
+func (r *ComparisonReport) Impacts() map[string]Impact {
+	impacts := make(map[string]Impact, len(r.Added)+len(r.Removed)+len(r.Changed))
+	for _, name := range r.Added {
+		impacts[name] = ImpactReread
+	}
+	for _, name := range r.Removed {
+		impacts[name] = ImpactReread
+	}
+	for _, change := range r.Changed {
+		impacts[change.Name] = change.Impact()
+	}
+	return impacts
+}
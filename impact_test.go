@@ -0,0 +1,32 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComparisonReportImpacts(t *testing.T) {
+	// Test restart, reread and cosmetic classification per program
+	// 测试每个程序的重启、重读和外观分类
+	oldRegistry := supervisordkratos.NewRegistry().
+		AddProgram(supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/api")).
+		AddProgram(supervisordkratos.NewProgramConfig("web", "/opt/web", "deploy", "/var/log/web"))
+	newRegistry := supervisordkratos.NewRegistry().
+		AddProgram(supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/api").WithStopWaitSecs(30)).
+		AddProgram(supervisordkratos.NewProgramConfig("web", "/opt/web", "deploy", "/var/log/web").WithResources(500, 256<<20)).
+		AddProgram(supervisordkratos.NewProgramConfig("worker", "/opt/worker", "deploy", "/var/log/worker"))
+
+	report := supervisordkratos.CompareDefinitions(oldRegistry, newRegistry)
+	t.Log(report.String())
+
+	require.Equal(t, map[string]supervisordkratos.Impact{
+		"api":    supervisordkratos.ImpactRestart,
+		"web":    supervisordkratos.ImpactCosmetic,
+		"worker": supervisordkratos.ImpactReread,
+	}, report.Impacts())
+	require.Equal(t, 1, report.RestartEstimate())
+	require.Equal(t, supervisordkratos.ImpactCosmetic, supervisordkratos.ClassifyOption("resources.cpu_millis"))
+	require.Equal(t, supervisordkratos.ImpactRestart, supervisordkratos.ClassifyOption("command"))
+}
@@ -0,0 +1,113 @@
+package supervisordkratos
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Selector label selector similar to kubectl, e.g. "tier=edge,env!=dev,team"
+// All requirements must match
+//
+// Selector 类似 kubectl 的标签选择器，例如 "tier=edge,env!=dev,team"
+// 所有条件都必须满足
+type Selector struct {
+	Requirements []*Requirement // Requirements joined with AND // 以 AND 连接的条件
+}
+
+// Requirement single selector condition on one label
+// Requirement 针对单个标签的选择条件
+type Requirement struct {
+	Key      string // Label key // 标签键
+	Operator string // "=", "!=" or "exists" // "="、"!=" 或 "exists"
+	Value    string // Expected value, blank with "exists" // 期望值，"exists" 时为空
+}
+
+// ParseSelector parse selector text, blank text selects everything
+// ParseSelector 解析选择器文本，空文本选择全部
+func ParseSelector(text string) (*Selector, error) {
+	selector := &Selector{Requirements: make([]*Requirement, 0)}
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		requirement := &Requirement{Operator: "exists"}
+		if key, value, ok := strings.Cut(part, "!="); ok {
+			requirement.Key, requirement.Operator, requirement.Value = key, "!=", value
+		} else if key, value, ok := strings.Cut(part, "="); ok {
+			requirement.Key, requirement.Operator, requirement.Value = key, "=", strings.TrimPrefix(value, "=")
+		} else {
+			requirement.Key = part
+		}
+		requirement.Key = strings.TrimSpace(requirement.Key)
+		requirement.Value = strings.TrimSpace(requirement.Value)
+		if requirement.Key == "" {
+			return nil, errors.Errorf("invalid selector requirement %q", part)
+		}
+		selector.Requirements = append(selector.Requirements, requirement)
+	}
+	return selector, nil
+}
+
+// Matches checks if labels satisfy every requirement
+// Matches 检查标签是否满足所有条件
+func (s *Selector) Matches(labels map[string]string) bool {
+	for _, requirement := range s.Requirements {
+		value, exists := labels[requirement.Key]
+		switch requirement.Operator {
+		case "=":
+			if !exists || value != requirement.Value {
+				return false
+			}
+		case "!=":
+			if exists && value == requirement.Value {
+				return false
+			}
+		default:
+			if !exists {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Select list programs whose labels match selector text, in registration order
+// Select 按注册顺序列出标签匹配选择器文本的程序
+func (r *Registry) Select(selector string) ([]*ProgramConfig, error) {
+	sel, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*ProgramConfig, 0)
+	seen := make(map[string]bool)
+	candidates := slices.Clone(r.programs)
+	for _, group := range r.groups {
+		candidates = append(candidates, group.Programs...)
+		candidates = append(candidates, group.Standalone...)
+	}
+	for _, program := range candidates {
+		if seen[program.Name] || !sel.Matches(program.Labels) {
+			continue
+		}
+		seen[program.Name] = true
+		results = append(results, program)
+	}
+	return results, nil
+}
+
+// RenderSelected generate program sections of programs matching selector text
+// RenderSelected 生成匹配选择器文本的程序段落
+func (r *Registry) RenderSelected(selector string) (string, error) {
+	programs, err := r.Select(selector)
+	if err != nil {
+		return "", err
+	}
+	document := NewDocument()
+	for _, program := range programs {
+		document.Add(NewProgramSection(r.resolvedOf(program)))
+	}
+	return document.String(), nil
+}
@@ -0,0 +1,52 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectorMatches(t *testing.T) {
+	// Test selector operators on labels
+	// 测试选择器运算符对标签的匹配
+	selector, err := supervisordkratos.ParseSelector("tier=edge, env!=dev, team")
+	require.NoError(t, err)
+	require.Len(t, selector.Requirements, 3)
+
+	require.True(t, selector.Matches(map[string]string{"tier": "edge", "env": "prod", "team": "payments"}))
+	require.True(t, selector.Matches(map[string]string{"tier": "edge", "team": "payments"}))
+	require.False(t, selector.Matches(map[string]string{"tier": "edge", "env": "dev", "team": "payments"}))
+	require.False(t, selector.Matches(map[string]string{"tier": "core", "team": "payments"}))
+	require.False(t, selector.Matches(map[string]string{"tier": "edge"}))
+
+	_, err = supervisordkratos.ParseSelector("=edge")
+	require.Error(t, err)
+}
+
+func TestRegistryRenderSelected(t *testing.T) {
+	// Test rendering subset of registry by labels
+	// 测试按标签渲染注册表的子集
+	gateway := supervisordkratos.NewProgramConfig("gateway", "/opt/gateway", "deploy", "/var/log/edge").
+		WithLabel("tier", "edge")
+	payments := supervisordkratos.NewProgramConfig("payments", "/opt/payments", "deploy", "/var/log/core").
+		WithLabel("tier", "core").
+		WithLabel("team", "payments")
+
+	registry := supervisordkratos.NewRegistry().
+		AddProgram(gateway).
+		AddGroup(supervisordkratos.NewGroupConfig("core").AddProgram(payments))
+
+	programs, err := registry.Select("team=payments")
+	require.NoError(t, err)
+	require.Len(t, programs, 1)
+	require.Equal(t, "payments", programs[0].Name)
+
+	content, err := registry.RenderSelected("tier=edge")
+	require.NoError(t, err)
+	require.Equal(t, supervisordkratos.GenerateProgramConfig(gateway), content)
+
+	programs, err = registry.Select("")
+	require.NoError(t, err)
+	require.Len(t, programs, 2)
+}
@@ -0,0 +1,144 @@
+package supervisordkratos
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ephemeralStartTimeout max wait for throwaway supervisord to answer on its socket
+// ephemeralStartTimeout 等待临时 supervisord 在套接字上响应的最长时间
+const ephemeralStartTimeout = 10 * time.Second
+
+// EphemeralSupervisord throwaway supervisord on a unique unix socket, for tests exercising real behavior
+// Everything lives in Dir and is removed by Close
+//
+// EphemeralSupervisord 使用唯一 unix 套接字的临时 supervisord，用于测试真实行为
+// 所有文件位于 Dir 中，并由 Close 删除
+type EphemeralSupervisord struct {
+	Dir        string     // Temp directory holding config, socket and logs // 存放配置、套接字和日志的临时目录
+	Socket     string     // Unix socket path // unix 套接字路径
+	ConfigPath string     // Root config path // 根配置路径
+	python     string     // python3 running supervisord and supervisorctl // 运行 supervisord 和 supervisorctl 的 python3
+	command    *exec.Cmd  // Running supervisord // 运行中的 supervisord
+	exited     chan error // Wait result of supervisord // supervisord 的 Wait 结果
+}
+
+// StartEphemeralSupervisord start supervisord in foreground with minimal root config including configText
+// Returns ErrSupervisordNotFound when python3 or the supervisor package is missing, letting tests skip
+//
+// StartEphemeralSupervisord 以前台方式启动 supervisord，最小根配置包含 configText
+// 当 python3 或 supervisor 包缺失时返回 ErrSupervisordNotFound，便于测试跳过
+func StartEphemeralSupervisord(configText string) (*EphemeralSupervisord, error) {
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		return nil, ErrSupervisordNotFound
+	}
+	if err := exec.Command(python, "-c", "import supervisor").Run(); err != nil {
+		return nil, ErrSupervisordNotFound
+	}
+	if err := Simulate(configText); err != nil {
+		return nil, err
+	}
+	// Short base dir keeps socket path below the unix socket length limit
+	// 较短的基础目录使套接字路径低于 unix 套接字长度限制
+	tempDir, err := os.MkdirTemp("", "sdk-")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	s := &EphemeralSupervisord{
+		Dir:        tempDir,
+		Socket:     filepath.Join(tempDir, "supervisor.sock"),
+		ConfigPath: filepath.Join(tempDir, "supervisord.conf"),
+		python:     python,
+		exited:     make(chan error, 1),
+	}
+	programsPath := filepath.Join(tempDir, "programs.conf")
+	root := NewDocument(
+		NewSection("supervisord").
+			Add("logfile", filepath.Join(tempDir, "supervisord.log")).
+			Add("pidfile", filepath.Join(tempDir, "supervisord.pid")).
+			Add("childlogdir", tempDir).
+			Add("nodaemon", "true"),
+		NewSection("unix_http_server").
+			Add("file", s.Socket),
+		NewSection("rpcinterface:supervisor").
+			Add("supervisor.rpcinterface_factory", "supervisor.rpcinterface:make_main_rpcinterface"),
+		NewSection("supervisorctl").
+			Add("serverurl", "unix://"+s.Socket),
+		NewSection("include").
+			Add("files", programsPath),
+	)
+	if err := os.WriteFile(programsPath, []byte(configText), 0o644); err != nil {
+		return nil, s.fail(errors.WithStack(err))
+	}
+	if err := os.WriteFile(s.ConfigPath, []byte(root.String()), 0o644); err != nil {
+		return nil, s.fail(errors.WithStack(err))
+	}
+
+	s.command = exec.Command(python, "-m", "supervisor.supervisord", "-n", "-c", s.ConfigPath)
+	if err := s.command.Start(); err != nil {
+		return nil, s.fail(errors.WithStack(err))
+	}
+	go func() { s.exited <- s.command.Wait() }()
+
+	deadline := time.Now().Add(ephemeralStartTimeout)
+	for {
+		if _, err := s.Ctl("pid"); err == nil {
+			return s, nil
+		}
+		select {
+		case err := <-s.exited:
+			s.command = nil
+			logText, _ := os.ReadFile(filepath.Join(tempDir, "supervisord.log"))
+			return nil, s.fail(errors.Errorf("supervisord exited early: %v: %s", err, strings.TrimSpace(string(logText))))
+		case <-time.After(100 * time.Millisecond):
+		}
+		if time.Now().After(deadline) {
+			return nil, s.fail(errors.Errorf("supervisord did not answer on %s within %s", s.Socket, ephemeralStartTimeout))
+		}
+	}
+}
+
+// Ctl run supervisorctl against this supervisord and return its output
+// Non-zero exit returns output together with error, e.g. status with stopped processes
+//
+// Ctl 针对该 supervisord 运行 supervisorctl 并返回输出
+// 非零退出时同时返回输出和错误，例如存在已停止进程时的 status
+func (s *EphemeralSupervisord) Ctl(args ...string) (string, error) {
+	output, err := exec.Command(s.python, append([]string{"-m", "supervisor.supervisorctl", "-c", s.ConfigPath}, args...)...).CombinedOutput()
+	if err != nil {
+		return string(output), errors.Wrapf(err, "supervisorctl %s", strings.Join(args, " "))
+	}
+	return string(output), nil
+}
+
+// Close stop supervisord with its processes and remove Dir
+// Close 停止 supervisord 及其进程并删除 Dir
+func (s *EphemeralSupervisord) Close() error {
+	return s.fail(nil)
+}
+
+// fail tear down whatever was started, then return cause
+// fail 清理已启动的内容，然后返回 cause
+func (s *EphemeralSupervisord) fail(cause error) error {
+	if s.command != nil {
+		_ = s.command.Process.Signal(syscall.SIGTERM)
+		select {
+		case <-s.exited:
+		case <-time.After(ephemeralStartTimeout):
+			_ = s.command.Process.Kill()
+			<-s.exited
+		}
+		s.command = nil
+	}
+	if err := os.RemoveAll(s.Dir); err != nil && cause == nil {
+		return errors.WithStack(err)
+	}
+	return cause
+}
@@ -0,0 +1,28 @@
+package supervisordkratos_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartEphemeralSupervisord(t *testing.T) {
+	// Test throwaway supervisord runs program and tears down, skipped when unavailable
+	// 测试临时 supervisord 运行程序并清理，不可用时跳过
+	supervisord, err := supervisordkratos.StartEphemeralSupervisord("[program:sleeper]\ncommand=/bin/sleep 60\nstartsecs=0\n")
+	if errors.Is(err, supervisordkratos.ErrSupervisordNotFound) {
+		t.Skip("supervisord not installed")
+	}
+	require.NoError(t, err)
+	defer func() { require.NoError(t, supervisord.Close()) }()
+
+	require.Eventually(t, func() bool {
+		output, _ := supervisord.Ctl("status", "sleeper")
+		t.Log(output)
+		return strings.Contains(output, "RUNNING")
+	}, 5*time.Second, 100*time.Millisecond)
+}
@@ -0,0 +1,76 @@
+package supervisordkratos
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrSupervisordNotFound returned when python3 or the supervisor package is not installed
+// ErrSupervisordNotFound 当 python3 或 supervisor 包未安装时返回
+var ErrSupervisordNotFound = errors.New("supervisord not installed")
+
+// supervisordCheckScript loads config with supervisord's own options parser,
+// which reads includes and builds process configs without starting anything
+//
+// supervisordCheckScript 使用 supervisord 自身的选项解析器加载配置，
+// 它会读取 include 并构建进程配置，但不会启动任何程序
+const supervisordCheckScript = `import sys
+try:
+    from supervisor.options import ServerOptions
+except ImportError:
+    sys.exit(3)
+ServerOptions().realize(args=["-c", sys.argv[1]])
+`
+
+// ValidateWithSupervisord asks installed supervisord whether it accepts config file at path
+// The file is included from a temp [supervisord] root, so conf.d snippets work as-is
+// Returns ErrSupervisordNotFound when supervisord is unavailable, letting CI skip the check
+//
+// ValidateWithSupervisord 询问已安装的 supervisord 是否接受 path 处的配置文件
+// 该文件会被临时的 [supervisord] 根配置包含，因此 conf.d 片段可直接使用
+// 当 supervisord 不可用时返回 ErrSupervisordNotFound，便于 CI 跳过该检查
+func ValidateWithSupervisord(path string) error {
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		return ErrSupervisordNotFound
+	}
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	tempDir, err := os.MkdirTemp("", "supervisordkratos-")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() { _ = os.RemoveAll(tempDir) }()
+
+	root := NewDocument(
+		NewSection("supervisord").
+			Add("logfile", filepath.Join(tempDir, "supervisord.log")).
+			Add("pidfile", filepath.Join(tempDir, "supervisord.pid")).
+			Add("childlogdir", tempDir),
+		NewSection("include").
+			Add("files", path),
+	)
+	rootPath := filepath.Join(tempDir, "supervisord.conf")
+	if err := os.WriteFile(rootPath, []byte(root.String()), 0o644); err != nil {
+		return errors.WithStack(err)
+	}
+
+	var stderr bytes.Buffer
+	command := exec.Command(python, "-c", supervisordCheckScript, rootPath)
+	command.Stderr = &stderr
+	if err := command.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 3 {
+			return ErrSupervisordNotFound
+		}
+		return errors.Errorf("supervisord rejected %s: %s", path, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package supervisordkratos_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWithSupervisord(t *testing.T) {
+	// Test generated config against installed supervisord, skipped when unavailable
+	// 测试生成的配置能被已安装的 supervisord 接受，不可用时跳过
+	program := supervisordkratos.NewProgramConfig("api", "/opt/api", "root", t.TempDir())
+	path := filepath.Join(t.TempDir(), "api.conf")
+	require.NoError(t, os.WriteFile(path, []byte(supervisordkratos.GenerateProgramConfig(program)), 0o644))
+
+	err := supervisordkratos.ValidateWithSupervisord(path)
+	if errors.Is(err, supervisordkratos.ErrSupervisordNotFound) {
+		t.Skip("supervisord not installed")
+	}
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("[program:api]\ncommand=/opt/api/bin/api\nstartsecs=soon\n"), 0o644))
+	require.Error(t, supervisordkratos.ValidateWithSupervisord(path))
+}
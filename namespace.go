@@ -0,0 +1,97 @@
+package supervisordkratos
+
+import (
+	"strings"
+
+	"github.com/yyle88/must"
+)
+
+// namespaceSep joins namespace levels and program names, e.g. payments.core.api
+// namespaceSep 连接命名空间层级和程序名称，例如 payments.core.api
+const namespaceSep = "."
+
+// Namespace emulates nested groups with dotted name prefixes
+// Each namespace holding programs renders as one [group:x] named by its path
+//
+// Namespace 使用点分名称前缀模拟嵌套组
+// 每个包含程序的命名空间渲染为一个以其路径命名的 [group:x]
+type Namespace struct {
+	Path     string           // Dotted path, e.g. "payments.core" // 点分路径，例如 "payments.core"
+	Programs []*ProgramConfig // Programs directly inside // 直接包含的程序
+	Children []*Namespace     // Nested namespaces // 嵌套的命名空间
+}
+
+// NewNamespace create new top-level Namespace
+// 创建新的顶层 Namespace
+func NewNamespace(name string) *Namespace {
+	must.Nice(name)
+	must.False(strings.Contains(name, namespaceSep))
+	return &Namespace{
+		Path:     name,
+		Programs: make([]*ProgramConfig, 0),
+		Children: make([]*Namespace, 0),
+	}
+}
+
+// Child create nested namespace under n and return it
+// Child 在 n 下创建嵌套命名空间并返回
+func (n *Namespace) Child(name string) *Namespace {
+	child := NewNamespace(name)
+	child.Path = n.Path + namespaceSep + name
+	n.Children = append(n.Children, child)
+	return child
+}
+
+// NewProgramConfig create program named path.name inside namespace
+// NewProgramConfig 在命名空间内创建名为 path.name 的程序
+func (n *Namespace) NewProgramConfig(name string, root string, userName string, slogRoot string) *ProgramConfig {
+	must.Nice(name)
+	program := NewProgramConfig(n.Path+namespaceSep+name, root, userName, slogRoot)
+	n.Programs = append(n.Programs, program)
+	return program
+}
+
+// Groups returns one GroupConfig per namespace holding programs, n first then descendants
+// Groups 为每个包含程序的命名空间返回一个 GroupConfig，先 n 后子孙
+func (n *Namespace) Groups() []*GroupConfig {
+	groups := make([]*GroupConfig, 0)
+	if len(n.Programs) > 0 {
+		group := NewGroupConfig(n.Path)
+		for _, program := range n.Programs {
+			group.AddProgram(program)
+		}
+		groups = append(groups, group)
+	}
+	for _, child := range n.Children {
+		groups = append(groups, child.Groups()...)
+	}
+	return groups
+}
+
+// Targets returns supervisorctl targets addressing every program under n, e.g. "payments.core:*"
+// Targets 返回可访问 n 下所有程序的 supervisorctl 目标，例如 "payments.core:*"
+func (n *Namespace) Targets() []string {
+	targets := make([]string, 0)
+	for _, group := range n.Groups() {
+		targets = append(targets, group.Name+":*")
+	}
+	return targets
+}
+
+// Contains checks if group or program name sits under namespace path
+// Contains 检查组或程序名称是否位于命名空间路径下
+func (n *Namespace) Contains(name string) bool {
+	return name == n.Path || strings.HasPrefix(name, n.Path+namespaceSep)
+}
+
+// Select filter supervisorctl status results down to processes under namespace
+// Select 将 supervisorctl 状态结果过滤为命名空间下的进程
+func (n *Namespace) Select(statuses []*ProcessStatus) []*ProcessStatus {
+	results := make([]*ProcessStatus, 0)
+	for _, status := range statuses {
+		if n.Contains(status.Group) {
+			results = append(results, status)
+		}
+	}
+	return results
+}
@@ -0,0 +1,48 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceGroups(t *testing.T) {
+	// Test nested namespaces render dotted programs and groups
+	// 测试嵌套命名空间渲染点分程序和组
+	payments := supervisordkratos.NewNamespace("payments")
+	payments.NewProgramConfig("gateway", "/opt/payments-gateway", "deploy", "/var/log/payments")
+	core := payments.Child("core")
+	core.NewProgramConfig("api", "/opt/payments-api", "deploy", "/var/log/payments")
+	core.NewProgramConfig("worker", "/opt/payments-worker", "deploy", "/var/log/payments")
+
+	groups := payments.Groups()
+	require.Len(t, groups, 2)
+	require.Equal(t, "payments", groups[0].Name)
+	require.Equal(t, "payments.core", groups[1].Name)
+
+	content, err := supervisordkratos.GenerateGroupsConfig(groups...)
+	require.NoError(t, err)
+	t.Log(content)
+	require.Contains(t, content, "[group:payments.core]\nprograms=payments.core.api,payments.core.worker\n")
+	require.Contains(t, content, "[program:payments.core.api]\n")
+
+	require.Equal(t, []string{"payments:*", "payments.core:*"}, payments.Targets())
+	require.Equal(t, []string{"payments.core:*"}, core.Targets())
+}
+
+func TestNamespaceSelect(t *testing.T) {
+	// Test addressing all processes under a namespace
+	// 测试访问命名空间下的所有进程
+	statuses, err := supervisordkratos.ParseSupervisorctlStatus(`payments:payments.gateway         RUNNING   pid 10, uptime 0:00:10
+payments.core:payments.core.api   RUNNING   pid 11, uptime 0:00:10
+paymentsx:paymentsx.api           RUNNING   pid 12, uptime 0:00:10
+`)
+	require.NoError(t, err)
+
+	payments := supervisordkratos.NewNamespace("payments")
+	core := payments.Child("core")
+	require.Len(t, payments.Select(statuses), 2)
+	require.Len(t, core.Select(statuses), 1)
+	require.False(t, payments.Contains("paymentsx"))
+}
@@ -8,8 +8,9 @@ package supervisordkratos
 // 帮助区分默认值和自定义指定的值
 // 泛型类型 T 允许在配置字段中灵活使用
 type Opt[T any] struct {
-	Value T    // Stored value // 存储的值
-	isSet bool // Track if value was set // 跟踪值是否已被设置
+	Value        T    // Stored value // 存储的值
+	isSet        bool // Track if value was set // 跟踪值是否已被设置
+	defaultValue T    // Constructor default kept after Set // Set 之后仍保留的构造默认值
 }
 
 // NewOpt creates new Opt with default value (not marked as set)
@@ -20,7 +21,7 @@ type Opt[T any] struct {
 // 值已存储但 isSet 标志保持 false
 // 使用 Set() 标记为自定义配置的值
 func NewOpt[T any](v T) *Opt[T] {
-	return &Opt[T]{Value: v, isSet: false}
+	return &Opt[T]{Value: v, isSet: false, defaultValue: v}
 }
 
 // Get returns current stored value
@@ -52,3 +53,18 @@ func (sv *Opt[T]) Set(v T) {
 func (sv *Opt[T]) IsSet() bool {
 	return sv.isSet
 }
+
+// Default returns value given to NewOpt, unaffected by Set()
+// Default 返回传给 NewOpt 的值，不受 Set() 影响
+func (sv *Opt[T]) Default() T {
+	return sv.defaultValue
+}
+
+// Effective returns Set value when set, otherwise the constructor default
+// Effective 已设置时返回设置值，否则返回构造默认值
+func (sv *Opt[T]) Effective() T {
+	if sv.isSet {
+		return sv.Value
+	}
+	return sv.defaultValue
+}
@@ -31,3 +31,15 @@ func TestOptAny(t *testing.T) {
 	opt.Set("false")
 	require.Equal(t, "false", opt.Get())
 }
+
+func TestOptDefault(t *testing.T) {
+	// Test Default keeps constructor value while Effective follows Set
+	// 测试 Default 保留构造值而 Effective 跟随 Set
+	opt := NewOpt(10)
+	require.Equal(t, 10, opt.Default())
+	require.Equal(t, 10, opt.Effective())
+
+	opt.Set(45)
+	require.Equal(t, 10, opt.Default())
+	require.Equal(t, 45, opt.Effective())
+}
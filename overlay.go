@@ -0,0 +1,73 @@
+package supervisordkratos
+
+import (
+	"strings"
+)
+
+// EmergencyStopMarker comment placed above group header of emergency stop overlays
+// EmergencyStopMarker 放在紧急停止覆盖配置组段头上方的注释
+const EmergencyStopMarker = "EMERGENCY STOP: every program forced to autostart=false, autorestart=false"
+
+// GenerateEmergencyStopOverlay generate group config variant that keeps every program down
+// Original group is not modified, sections keep their layout
+//
+// GenerateEmergencyStopOverlay 生成使所有程序保持停止的组配置变体
+// 原始组不会被修改，段落保持原有布局
+func GenerateEmergencyStopOverlay(group *GroupConfig) string {
+	document := NewGroupDocument(group)
+	for _, section := range document.Sections {
+		if strings.HasPrefix(section.Name, "program:") {
+			section.Set("autostart", "false")
+			section.Set("autorestart", "false")
+		}
+	}
+	document.Sections[0].Comments = append(document.Sections[0].Comments, EmergencyStopMarker)
+	return document.String()
+}
+
+// EmergencyStopCommands supervisorctl commands freezing group after overlay is written
+// Stops processes first, then reloads group so the overlay holds across supervisord restarts
+// Standalone programs are their own groups, each is stopped and updated by name
+//
+// EmergencyStopCommands 写入覆盖配置后冻结组的 supervisorctl 命令
+// 先停止进程，再重新加载组，使覆盖配置在 supervisord 重启后仍然生效
+// 独立程序各自成组，按名称逐个停止和更新
+func EmergencyStopCommands(supervisorctl string, group *GroupConfig) []string {
+	commands := make([]string, 0)
+	if len(group.Programs) > 0 {
+		commands = append(commands, supervisorctl+" stop "+group.Name+":*")
+	}
+	for _, program := range group.Standalone {
+		target := program.Name
+		if program.NumProcs.Get() > 1 {
+			target += ":*"
+		}
+		commands = append(commands, supervisorctl+" stop "+target)
+	}
+	commands = append(commands, supervisorctl+" reread")
+	if len(group.Programs) > 0 {
+		commands = append(commands, supervisorctl+" update "+group.Name)
+	}
+	for _, program := range group.Standalone {
+		commands = append(commands, supervisorctl+" update "+program.Name)
+	}
+	return commands
+}
+
+// MaintenancePlaceholder lightweight command keeping program slot RUNNING during maintenance
+// MaintenancePlaceholder 维护期间使程序保持 RUNNING 的轻量级命令
+const MaintenancePlaceholder = "/bin/sleep infinity"
+
+// MaintenanceMarker comment placed above [program:x] header of programs in maintenance
+// MaintenanceMarker 放在维护中程序 [program:x] 段头上方的注释
+const MaintenanceMarker = "MAINTENANCE: command replaced with placeholder"
+
+// WithMaintenance swap command with placeholder while keeping section managed
+// Use MaintenancePlaceholder or a health stub, blank placeholder restores the binary
+//
+// WithMaintenance 将命令替换为占位命令，同时保持段落受管理
+// 使用 MaintenancePlaceholder 或健康检查桩，空占位命令恢复原二进制
+func (p *ProgramConfig) WithMaintenance(placeholder string) *ProgramConfig {
+	p.Maintenance.Set(placeholder)
+	return p
+}
@@ -0,0 +1,110 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmergencyStopOverlay(t *testing.T) {
+	// Test overlay forces every program down without touching the group
+	// 测试覆盖配置强制停止所有程序且不修改原始组
+	api := supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/services").
+		WithAutoRestart(true)
+	worker := supervisordkratos.NewProgramConfig("worker", "/opt/worker", "deploy", "/var/log/services")
+	group := supervisordkratos.NewGroupConfig("services").AddProgram(api).AddProgram(worker)
+	original := supervisordkratos.GenerateGroupConfig(group)
+
+	content := supervisordkratos.GenerateEmergencyStopOverlay(group)
+	t.Log(content)
+
+	const expected = `; EMERGENCY STOP: every program forced to autostart=false, autorestart=false
+[group:services]
+programs=api,worker
+
+
+[program:api]
+user            = deploy
+directory       = /opt/api
+command         = /opt/api/bin/api
+autorestart     = false
+stdout_logfile  = /var/log/services/api.log
+stderr_logfile  = /var/log/services/api.err
+autostart       = false
+
+[program:worker]
+user            = deploy
+directory       = /opt/worker
+command         = /opt/worker/bin/worker
+stdout_logfile  = /var/log/services/worker.log
+stderr_logfile  = /var/log/services/worker.err
+autostart       = false
+autorestart     = false
+`
+
+	require.Equal(t, expected, content)
+	require.Equal(t, original, supervisordkratos.GenerateGroupConfig(group))
+
+	require.Equal(t, []string{
+		"supervisorctl stop services:*",
+		"supervisorctl reread",
+		"supervisorctl update services",
+	}, supervisordkratos.EmergencyStopCommands("supervisorctl", group))
+}
+
+func TestEmergencyStopCommandsStandalone(t *testing.T) {
+	// Test standalone programs are stopped and updated by name, empty group is skipped
+	// 测试独立程序按名称停止和更新，空组被跳过
+	cron := supervisordkratos.NewProgramConfig("cron", "/opt/cron", "deploy", "/var/log/services")
+	worker := supervisordkratos.NewProgramConfig("worker", "/opt/worker", "deploy", "/var/log/services").
+		WithNumProcs(2).FixProcessName()
+	group := supervisordkratos.NewGroupConfig("services").AddStandaloneProgram(cron).AddStandaloneProgram(worker)
+
+	require.Equal(t, []string{
+		"supervisorctl stop cron",
+		"supervisorctl stop worker:*",
+		"supervisorctl reread",
+		"supervisorctl update cron",
+		"supervisorctl update worker",
+	}, supervisordkratos.EmergencyStopCommands("supervisorctl", group))
+
+	group.AddProgram(supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/services"))
+	require.Equal(t, []string{
+		"supervisorctl stop services:*",
+		"supervisorctl stop cron",
+		"supervisorctl stop worker:*",
+		"supervisorctl reread",
+		"supervisorctl update services",
+		"supervisorctl update cron",
+		"supervisorctl update worker",
+	}, supervisordkratos.EmergencyStopCommands("supervisorctl", group))
+}
+
+func TestWithMaintenance(t *testing.T) {
+	// Test placeholder replaces command and is reversible
+	// 测试占位命令替换原命令且可以恢复
+	program := supervisordkratos.NewProgramConfig(
+		"api",
+		"/opt/api",
+		"deploy",
+		"/var/log/api",
+	).WithMaintenance(supervisordkratos.MaintenancePlaceholder)
+
+	content := supervisordkratos.GenerateProgramConfig(program)
+	t.Log(content)
+
+	const expected = `; MAINTENANCE: command replaced with placeholder
+[program:api]
+user            = deploy
+directory       = /opt/api
+command         = /bin/sleep infinity
+stdout_logfile  = /var/log/api/api.log
+stderr_logfile  = /var/log/api/api.err
+`
+
+	require.Equal(t, expected, content)
+
+	program.WithMaintenance("")
+	require.Contains(t, supervisordkratos.GenerateProgramConfig(program), "command         = /opt/api/bin/api\n")
+}
@@ -0,0 +1,376 @@
+package supervisordkratos
+
+import (
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ParseDocument parse supervisord INI text into Document
+// Understands [section] headers, key=value entries, comments and indented continuation lines
+// Inline comments after values are stripped like supervisord does, and kept as entry comments
+// Blank lines between sections are kept as Padding so generated text parses back unchanged
+// Comment lines attach to the next section header or entry, trailing ones to Document.Trailer
+//
+// ParseDocument 将 supervisord INI 文本解析为 Document
+// 支持 [section] 段头、key=value 条目、注释和缩进的续行
+// 值后面的行内注释与 supervisord 一样被去掉，并保存为条目注释
+// 段落之间的空行保存为 Padding，使生成的文本可以原样解析回来
+// 注释行附加到下一个段头或条目，末尾的注释保存到 Document.Trailer
+func ParseDocument(text string) (*Document, error) {
+	document := NewDocument()
+	var section *Section
+	var entry *Entry
+	var comments []string
+	var compact = true
+	var blanks = 0
+
+	for idx, line := range strings.Split(text, "\n") {
+		num := idx + 1
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			blanks++
+			entry = nil
+		case strings.HasPrefix(trimmed, ";") || strings.HasPrefix(trimmed, "#"):
+			comments = append(comments, strings.TrimSpace(trimmed[1:]))
+			entry = nil
+		case line[0] == ' ' || line[0] == '\t':
+			// Indented line continues previous entry value
+			// 缩进行延续上一个条目的值
+			if entry == nil {
+				return nil, errors.Errorf("line %d: continuation line without entry", num)
+			}
+			value, comment := cutInlineComment(trimmed)
+			entry.Value += "\n" + value
+			if comment != "" {
+				entry.Comments = append(entry.Comments, comment)
+			}
+		case strings.HasPrefix(trimmed, "["):
+			if !strings.HasSuffix(trimmed, "]") {
+				return nil, errors.Errorf("line %d: unclosed section header %q", num, trimmed)
+			}
+			name := strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+			if name == "" {
+				return nil, errors.Errorf("line %d: blank section name", num)
+			}
+			if section != nil {
+				section.Compact = compact && len(section.Entries) > 0
+				section.Padding = max(blanks-1, 0)
+			}
+			section = NewSection(name)
+			section.Comments = comments
+			document.Add(section)
+			comments = nil
+			entry = nil
+			compact = true
+			blanks = 0
+		default:
+			if section == nil {
+				return nil, errors.Errorf("line %d: entry outside section", num)
+			}
+			content, comment := cutInlineComment(trimmed)
+			key, value, ok := strings.Cut(content, "=")
+			if !ok {
+				return nil, errors.Errorf("line %d: missing '=' in %q", num, trimmed)
+			}
+			if strings.TrimSpace(key) == "" {
+				return nil, errors.Errorf("line %d: blank key", num)
+			}
+			if key != strings.TrimSpace(key) || value != strings.TrimSpace(value) {
+				compact = false
+			}
+			section.Add(strings.TrimSpace(key), strings.TrimSpace(value))
+			entry = section.Entries[len(section.Entries)-1]
+			entry.Comments = comments
+			if comment != "" {
+				entry.Comments = append(entry.Comments, comment)
+			}
+			comments = nil
+			blanks = 0
+		}
+	}
+	if section != nil {
+		section.Compact = compact && len(section.Entries) > 0
+	}
+	document.Trailer = comments
+	return document, nil
+}
+
+// cutInlineComment split off " ;comment" or " #comment" the way supervisord's ConfigParser does,
+// a prefix only starts a comment after whitespace, so "a;b" keeps its value
+//
+// cutInlineComment 按 supervisord ConfigParser 的方式拆分出 " ;comment" 或 " #comment"，
+// 前缀只有在空白之后才表示注释，因此 "a;b" 保持原值
+func cutInlineComment(line string) (string, string) {
+	for idx := 1; idx < len(line); idx++ {
+		if (line[idx] == ';' || line[idx] == '#') && (line[idx-1] == ' ' || line[idx-1] == '\t') {
+			return strings.TrimRight(line[:idx], " \t"), strings.TrimSpace(line[idx+1:])
+		}
+	}
+	return line, ""
+}
+
+// Roundtrip parse text and render it back as normalized text
+// Applying Roundtrip to its own output returns the same text
+//
+// Roundtrip 解析文本并渲染回规范化文本
+// 对其输出再次应用 Roundtrip 返回相同的文本
+func Roundtrip(text string) (string, error) {
+	document, err := ParseDocument(text)
+	if err != nil {
+		return "", err
+	}
+	return document.String(), nil
+}
+
+// ParseMode controls how unknown program options are handled
+// ParseMode 控制如何处理未知的程序选项
+type ParseMode int
+
+const (
+	// ParseStrict rejects unknown options with error
+	// ParseStrict 遇到未知选项时返回错误
+	ParseStrict ParseMode = iota
+	// ParseLenient keeps unknown options in RawOptions and re-emits them
+	// ParseLenient 将未知选项保存在 RawOptions 中并重新输出
+	ParseLenient
+)
+
+// ParseProgramConfigs parse every [program:x] section in text into ProgramConfig
+// Other sections are skipped
+//
+// ParseProgramConfigs 将文本中每个 [program:x] 段落解析为 ProgramConfig
+// 其他段落会被跳过
+func ParseProgramConfigs(text string, mode ParseMode) ([]*ProgramConfig, error) {
+	document, err := ParseDocument(text)
+	if err != nil {
+		return nil, err
+	}
+	programs := make([]*ProgramConfig, 0, len(document.Sections))
+	for _, section := range document.Sections {
+		if !strings.HasPrefix(section.Name, "program:") {
+			continue
+		}
+		program, err := ParseProgramSection(section, mode)
+		if err != nil {
+			return nil, err
+		}
+		programs = append(programs, program)
+	}
+	return programs, nil
+}
+
+// ParseProgramSection convert [program:x] Section into ProgramConfig
+// Derived options (command, log paths) must match what ProgramConfig would generate
+//
+// ParseProgramSection 将 [program:x] 段落转换为 ProgramConfig
+// 派生选项（command、日志路径）必须与 ProgramConfig 生成的值一致
+func ParseProgramSection(section *Section, mode ParseMode) (*ProgramConfig, error) {
+	name, ok := strings.CutPrefix(section.Name, "program:")
+	if !ok || name == "" {
+		return nil, errors.Errorf("section %s: not a program section", section.Name)
+	}
+	values := make(map[string]string, len(section.Entries))
+	for _, entry := range section.Entries {
+		values[entry.Key] = entry.Value
+	}
+
+	// Required options to rebuild the program
+	// 重建程序所需的必填选项
+	userName := values["user"]
+	root := values["directory"]
+	if userName == "" || root == "" {
+		return nil, errors.Errorf("program %s: user and directory are required", name)
+	}
+	// Missing or AUTO stdout_logfile means supervisord AUTO logs
+	// stdout_logfile 缺失或为 AUTO 表示 supervisord AUTO 日志
+	var program *ProgramConfig
+	if stdoutLogfile := values["stdout_logfile"]; stdoutLogfile == "" || stdoutLogfile == AutoLogfile {
+		program = NewAutoLogsProgramConfig(name, root, userName)
+	} else {
+		program = NewProgramConfig(name, root, userName, TargetLinux.Dir(stdoutLogfile))
+		program.WithInstanceLogs(strings.HasSuffix(stdoutLogfile, processNumSuffix+".log"))
+	}
+	// Binary named apart from program, like tenant programs
+	// 二进制名称与程序名称不同，例如租户程序
+	if command := values["command"]; TargetLinux.Dir(command) == TargetLinux.Join(root, "bin") && path.Base(command) != name {
+		program.WithBinaryName(path.Base(command))
+	}
+	for _, derived := range []*Entry{
+		{Key: "command", Value: program.commandPath()},
+		{Key: "stdout_logfile", Value: program.stdoutLogfile()},
+		{Key: "stderr_logfile", Value: program.stderrLogfile()},
+	} {
+		if value, exists := values[derived.Key]; exists && value != derived.Value {
+			return nil, errors.Errorf("program %s: %s %q differs from derived %q", name, derived.Key, value, derived.Value)
+		}
+	}
+	if values["stdout_logfile_maxbytes"] != values["stderr_logfile_maxbytes"] {
+		return nil, errors.Errorf("program %s: stdout and stderr logfile_maxbytes differ", name)
+	}
+	if values["stdout_logfile_backups"] != values["stderr_logfile_backups"] {
+		return nil, errors.Errorf("program %s: stdout and stderr logfile_backups differ", name)
+	}
+
+	program.WithComment("", section.Comments...)
+	for _, entry := range section.Entries {
+		program.WithComment(entry.Key, entry.Comments...)
+		if err := applyProgramOption(program, entry); err != nil {
+			if mode == ParseLenient && errors.Is(err, errUnknownOption) {
+				program.RawOptions = append(program.RawOptions, &Entry{Key: entry.Key, Value: entry.Value})
+				continue
+			}
+			return nil, errors.WithMessagef(err, "program %s", name)
+		}
+	}
+	return program, nil
+}
+
+// errUnknownOption marks options that ProgramConfig has no field to hold
+// errUnknownOption 标记 ProgramConfig 没有字段可以保存的选项
+var errUnknownOption = errors.New("unknown option")
+
+// applyProgramOption set ProgramConfig field from one parsed entry
+// applyProgramOption 根据一个解析出的条目设置 ProgramConfig 字段
+func applyProgramOption(program *ProgramConfig, entry *Entry) error {
+	var err error
+	switch entry.Key {
+	case "user", "directory", "command", "stdout_logfile", "stderr_logfile":
+		// Consumed when building the program
+		// 构建程序时已经使用
+	case "environment":
+		var environment map[string]string
+		if environment, err = splitSsMap(entry.Value, ","); err == nil {
+			program.WithEnvironment(environment)
+		}
+	case "autostart":
+		err = parseOpt(program.AutoStart, entry.Value, strconv.ParseBool)
+	case "autorestart":
+		switch entry.Value {
+		case "true", "false":
+			program.WithAutoRestart(entry.Value == "true")
+		case "unexpected":
+			program.WithAutoRestartMode(entry.Value)
+		default:
+			err = errors.Errorf("invalid value %q", entry.Value)
+		}
+	case "startretries":
+		err = parseOpt(program.StartRetries, entry.Value, strconv.Atoi)
+	case "startsecs":
+		err = parseOpt(program.StartSecs, entry.Value, strconv.Atoi)
+	case "stdout_logfile_maxbytes", "stderr_logfile_maxbytes":
+		program.WithLogMaxBytes(entry.Value)
+	case "stdout_logfile_backups", "stderr_logfile_backups":
+		err = parseOpt(program.LogBackups, entry.Value, strconv.Atoi)
+	case "redirect_stderr":
+		err = parseOpt(program.RedirectStderr, entry.Value, strconv.ParseBool)
+	case "stopasgroup":
+		err = parseOpt(program.StopAsGroup, entry.Value, strconv.ParseBool)
+	case "stopwaitsecs":
+		err = parseOpt(program.StopWaitSecs, entry.Value, strconv.Atoi)
+	case "killasgroup":
+		err = parseOpt(program.KillAsGroup, entry.Value, strconv.ParseBool)
+	case "stopsignal":
+		program.WithStopSignal(entry.Value)
+	case "priority":
+		err = parseOpt(program.Priority, entry.Value, strconv.Atoi)
+	case "exitcodes":
+		var exitCodes []int
+		if exitCodes, err = splitInts(entry.Value, ","); err == nil {
+			program.WithExitCodes(exitCodes)
+		}
+	case "numprocs":
+		err = parseOpt(program.NumProcs, entry.Value, strconv.Atoi)
+	case "process_name":
+		program.WithProcessName(entry.Value)
+	default:
+		return errors.WithMessagef(errUnknownOption, "%s", entry.Key)
+	}
+	return errors.WithMessagef(err, "option %s", entry.Key)
+}
+
+// parseOpt parse text with parseFunc and set it into opt
+// parseOpt 使用 parseFunc 解析文本并设置到 opt 中
+func parseOpt[T any](opt *Opt[T], text string, parseFunc func(string) (T, error)) error {
+	value, err := parseFunc(text)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	opt.Set(value)
+	return nil
+}
+
+// splitInts converts sep-separated text back to int slice
+// splitInts 将分隔符分隔的文本转换回整数切片
+func splitInts(text string, sep string) ([]int, error) {
+	results := make([]int, 0)
+	for _, item := range strings.Split(text, sep) {
+		value, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		results = append(results, value)
+	}
+	return results, nil
+}
+
+// splitSsMap converts name=value pairs joined with sep back to string map
+// Double-quoted values may contain sep
+//
+// splitSsMap 将由分隔符连接的键值对转换回字符串映射
+// 双引号包裹的值可以包含分隔符
+func splitSsMap(text string, sep string) (map[string]string, error) {
+	results := make(map[string]string)
+	for _, pair := range splitQuoted(text, sep) {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, errors.Errorf("invalid environment pair %q", pair)
+		}
+		results[strings.TrimSpace(key)] = unquoteEnvValue(strings.TrimSpace(value))
+	}
+	return results, nil
+}
+
+// unquoteEnvValue strip surrounding double or single quotes as supervisord shlex parsing does
+// Non-POSIX shlex has no escapes, backslashes stay literal, unquoted values are returned unchanged
+//
+// unquoteEnvValue 按 supervisord shlex 解析的方式去除两侧的双引号或单引号
+// 非 POSIX 模式的 shlex 没有转义，反斜杠保持原样，未加引号的值原样返回
+func unquoteEnvValue(value string) string {
+	if len(value) < 2 || value[0] != value[len(value)-1] || (value[0] != '"' && value[0] != '\'') {
+		return value
+	}
+	return value[1 : len(value)-1]
+}
+
+// splitQuoted splits text on sep, ignoring sep inside double or single quotes
+// Quotes close at the next matching quote character, there are no escapes
+//
+// splitQuoted 按分隔符拆分文本，忽略双引号或单引号内的分隔符
+// 引号在下一个相同的引号字符处结束，没有转义
+func splitQuoted(text string, sep string) []string {
+	var parts []string
+	var quote byte
+	var start int
+	for idx := 0; idx < len(text); idx++ {
+		switch {
+		case quote != 0:
+			if text[idx] == quote {
+				quote = 0
+			}
+		case text[idx] == '"' || text[idx] == '\'':
+			quote = text[idx]
+		case strings.HasPrefix(text[idx:], sep):
+			parts = append(parts, text[start:idx])
+			start = idx + len(sep)
+		}
+	}
+	return append(parts, text[start:])
+}
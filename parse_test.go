@@ -0,0 +1,249 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundtripGenerated(t *testing.T) {
+	// Test generated configs parse back unchanged
+	// 测试生成的配置可以原样解析回来
+	program := supervisordkratos.NewProgramConfig(
+		"api-gateway",
+		"/opt/gateway",
+		"deploy",
+		"/var/log/cluster",
+	).WithPriority(1).
+		WithNumProcs(2).
+		WithProcessName("%(program_name)s-%(process_num)02d").
+		WithLogMaxBytes("200MB")
+
+	worker := supervisordkratos.NewProgramConfig(
+		"worker",
+		"/opt/worker",
+		"deploy",
+		"/var/log/cluster",
+	).WithAutoStart(false)
+
+	group := supervisordkratos.NewGroupConfig("cluster").
+		AddProgram(program).
+		AddProgram(worker)
+
+	for _, text := range []string{
+		supervisordkratos.GenerateProgramConfig(program),
+		supervisordkratos.GenerateGroupConfig(group),
+	} {
+		normalized, err := supervisordkratos.Roundtrip(text)
+		require.NoError(t, err)
+		require.Equal(t, text, normalized)
+	}
+}
+
+func TestRoundtripHandWritten(t *testing.T) {
+	// Test hand-written config normalizes and stays stable
+	// 测试手写配置规范化后保持稳定
+	const text = `; legacy config
+[group:legacy]
+programs = api,worker
+[program:api]
+command=/opt/api/bin/api
+environment = A=1,
+	B=2
+
+
+
+[program:worker]
+command   =   /opt/worker/bin/worker
+`
+
+	normalized, err := supervisordkratos.Roundtrip(text)
+	require.NoError(t, err)
+	t.Log(normalized)
+
+	const expected = `; legacy config
+[group:legacy]
+programs        = api,worker
+
+[program:api]
+command         = /opt/api/bin/api
+environment     = A=1,
+    B=2
+
+
+
+[program:worker]
+command         = /opt/worker/bin/worker
+`
+
+	require.Equal(t, expected, normalized)
+
+	again, err := supervisordkratos.Roundtrip(normalized)
+	require.NoError(t, err)
+	require.Equal(t, normalized, again)
+}
+
+func TestParseDocumentErrors(t *testing.T) {
+	// Test malformed input returns errors
+	// 测试格式错误的输入返回错误
+	for _, text := range []string{
+		"command = /bin/x\n",
+		"[program:x\n",
+		"[]\n",
+		"[program:x]\ncommand\n",
+		"[program:x]\n = value\n",
+		"[program:x]\n  continued\n",
+	} {
+		_, err := supervisordkratos.ParseDocument(text)
+		require.Error(t, err, text)
+	}
+}
+
+func FuzzParseDocument(f *testing.F) {
+	// Fuzz parser with operator-edited style inputs, must never panic
+	// 使用操作员编辑风格的输入对解析器进行模糊测试，不得崩溃
+	f.Add("[program:x]\ncommand = /bin/x\n")
+	f.Add("[group:g]\nprograms=a,b\n\n\n[program:a]\ncommand=/a\n")
+	f.Add("; comment\n[program:x]\nenvironment = A=1,\n\tB=2\n")
+	f.Add("[program:x\n = \n  \n[]")
+	f.Fuzz(func(t *testing.T, text string) {
+		normalized, err := supervisordkratos.Roundtrip(text)
+		if err != nil {
+			return
+		}
+		// Normalized text is stable under another round trip
+		// 规范化文本在再次往返后保持稳定
+		again, err := supervisordkratos.Roundtrip(normalized)
+		require.NoError(t, err)
+		require.Equal(t, normalized, again)
+	})
+}
+
+func TestParseProgramConfigsModes(t *testing.T) {
+	// Test strict mode rejects unknown options while lenient keeps them
+	// 测试严格模式拒绝未知选项而宽松模式保留它们
+	const text = `[program:legacy]
+user            = deploy
+directory       = /opt/legacy
+command         = /opt/legacy/bin/legacy
+environment     = APP_ENV=production
+startretries    = 5
+stdout_logfile  = /var/log/legacy/legacy.log
+stderr_logfile  = /var/log/legacy/legacy.err
+umask           = 022
+priority        = 100
+`
+
+	_, err := supervisordkratos.ParseProgramConfigs(text, supervisordkratos.ParseStrict)
+	require.Error(t, err)
+
+	programs, err := supervisordkratos.ParseProgramConfigs(text, supervisordkratos.ParseLenient)
+	require.NoError(t, err)
+	require.Len(t, programs, 1)
+
+	program := programs[0]
+	require.Equal(t, "legacy", program.Name)
+	require.Equal(t, "/var/log/legacy", program.SlogRoot)
+	require.Equal(t, 5, program.StartRetries.Get())
+	require.Equal(t, map[string]string{"APP_ENV": "production"}, program.Environment.Get())
+	require.Len(t, program.RawOptions, 1)
+
+	// Unknown options are re-emitted after known ones
+	// 未知选项在已知选项之后重新输出
+	const expected = `[program:legacy]
+user            = deploy
+directory       = /opt/legacy
+command         = /opt/legacy/bin/legacy
+environment     = APP_ENV=production
+startretries    = 5
+stdout_logfile  = /var/log/legacy/legacy.log
+stderr_logfile  = /var/log/legacy/legacy.err
+priority        = 100
+umask           = 022
+`
+
+	require.Equal(t, expected, supervisordkratos.GenerateProgramConfig(program))
+}
+
+func TestParseProgramConfigsErrors(t *testing.T) {
+	// Test values that ProgramConfig cannot represent
+	// 测试 ProgramConfig 无法表示的值
+	for _, text := range []string{
+		"[program:x]\ndirectory = /opt/x\n",
+		"[program:x]\nuser = deploy\ndirectory = /opt/x\nstderr_logfile = /var/log/x.err\n",
+		"[program:x]\nuser = deploy\ndirectory = /opt/x\ncommand = /usr/bin/x\nstdout_logfile = /var/log/x.log\n",
+		"[program:x]\nuser = deploy\ndirectory = /opt/x\nstdout_logfile = /var/log/x.log\nstartretries = many\n",
+		"[program:x]\nuser = deploy\ndirectory = /opt/x\nstdout_logfile = /var/log/x.log\nstdout_logfile_maxbytes = 1MB\n",
+	} {
+		_, err := supervisordkratos.ParseProgramConfigs(text, supervisordkratos.ParseLenient)
+		require.Error(t, err, text)
+	}
+}
+
+func TestParseKeepsComments(t *testing.T) {
+	// Test comments survive parse and generate cycle
+	// 测试注释在解析和生成循环中保留
+	const text = `; owner: payments team
+[program:billing]
+user            = deploy
+directory       = /opt/billing
+command         = /opt/billing/bin/billing
+; raised after incident 42
+startretries    = 8
+stdout_logfile  = /var/log/billing/billing.log
+stderr_logfile  = /var/log/billing/billing.err
+`
+
+	programs, err := supervisordkratos.ParseProgramConfigs(text, supervisordkratos.ParseStrict)
+	require.NoError(t, err)
+	require.Len(t, programs, 1)
+
+	content := supervisordkratos.GenerateProgramConfig(programs[0])
+	t.Log(content)
+	require.Equal(t, text, content)
+}
+
+func TestParseInlineComments(t *testing.T) {
+	// Test inline comments are stripped from values like supervisord does and kept as entry comments
+	// 测试行内注释像 supervisord 一样从值中去掉，并保存为条目注释
+	const text = `[program:billing]
+user            = deploy
+directory       = /opt/billing
+command         = /opt/billing/bin/billing
+priority        = 100 ; started before gateways
+autostart       = true # on boot
+stdout_logfile  = /var/log/billing/billing.log
+stderr_logfile  = /var/log/billing/billing.err
+environment     = TOKEN=a;b
+`
+
+	programs, err := supervisordkratos.ParseProgramConfigs(text, supervisordkratos.ParseStrict)
+	require.NoError(t, err)
+	require.Len(t, programs, 1)
+
+	program := programs[0]
+	require.Equal(t, 100, program.Priority.Get())
+	require.True(t, program.AutoStart.Get())
+	// Without whitespace before it, ';' is part of the value
+	// 前面没有空白时，';' 是值的一部分
+	require.Equal(t, map[string]string{"TOKEN": "a;b"}, program.Environment.Get())
+
+	content := supervisordkratos.GenerateProgramConfig(program)
+	t.Log(content)
+
+	const expected = `[program:billing]
+user            = deploy
+directory       = /opt/billing
+command         = /opt/billing/bin/billing
+environment     = TOKEN=a;b
+; on boot
+autostart       = true
+stdout_logfile  = /var/log/billing/billing.log
+stderr_logfile  = /var/log/billing/billing.err
+; started before gateways
+priority        = 100
+`
+
+	require.Equal(t, expected, content)
+}
@@ -0,0 +1,146 @@
+package supervisordkratos
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/pkg/errors"
+	"github.com/yyle88/must"
+)
+
+// PriorityBand base priority of a service tier, low bands start first
+// Each band spans PriorityBandWidth values for ordering inside the tier
+//
+// PriorityBand 服务层级的基础优先级，小值先启动
+// 每个区间包含 PriorityBandWidth 个值，用于层级内排序
+type PriorityBand int
+
+const (
+	PriorityInfrastructure PriorityBand = 100 // Registries, config centers, proxies // 注册中心、配置中心、代理
+	PriorityGateway        PriorityBand = 200 // API gateways, edge services // API 网关、边缘服务
+	PriorityService        PriorityBand = 500 // Business services // 业务服务
+	PriorityWorker         PriorityBand = 800 // Queue consumers, batch workers // 队列消费者、批处理任务
+)
+
+// PriorityBandWidth count of priority values inside one band
+// PriorityBandWidth 单个区间内的优先级数量
+const PriorityBandWidth = 100
+
+// WithPriorityBand set priority to band plus offset inside the band
+// Offset must be in [0, PriorityBandWidth)
+//
+// WithPriorityBand 将优先级设置为区间基础值加区间内偏移
+// 偏移量必须在 [0, PriorityBandWidth) 范围内
+func (p *ProgramConfig) WithPriorityBand(band PriorityBand, offset int) *ProgramConfig {
+	must.True(offset >= 0 && offset < PriorityBandWidth)
+	p.Priority.Set(int(band) + offset)
+	return p
+}
+
+// PriorityBandOf returns band containing priority, false when outside known bands
+// PriorityBandOf 返回包含该优先级的区间，不在已知区间内时返回 false
+func PriorityBandOf(priority int) (PriorityBand, bool) {
+	for _, band := range []PriorityBand{PriorityInfrastructure, PriorityGateway, PriorityService, PriorityWorker} {
+		if priority >= int(band) && priority < int(band)+PriorityBandWidth {
+			return band, true
+		}
+	}
+	return 0, false
+}
+
+// WithDependsOn declare programs that must start before this one
+// Priorities are then checked to keep them strictly ordered
+//
+// WithDependsOn 声明必须在本程序之前启动的程序
+// 随后会检查优先级以保持严格顺序
+func (p *ProgramConfig) WithDependsOn(names ...string) *ProgramConfig {
+	for _, name := range names {
+		p.DependsOn = append(p.DependsOn, must.Nice(name))
+	}
+	return p
+}
+
+// checkDependencyOrder reports dependencies not starting strictly before dependents
+// supervisord does not promise an order between equal priorities, so ties are errors
+// Dependencies outside programs are ignored
+//
+// checkDependencyOrder 报告未严格先于依赖方启动的依赖
+// supervisord 不保证相同优先级之间的顺序，因此相同优先级视为错误
+// 忽略不在 programs 中的依赖
+func checkDependencyOrder(programs []*ProgramConfig) error {
+	priorities := make(map[string]int, len(programs))
+	for _, program := range programs {
+		priorities[program.Name] = program.Priority.Get()
+	}
+	for _, program := range programs {
+		for _, name := range program.DependsOn {
+			priority, ok := priorities[name]
+			if !ok {
+				continue
+			}
+			if priority == program.Priority.Get() {
+				return errors.Errorf("program %s: priority %d duplicates dependency %s, start order is not guaranteed", program.Name, priority, name)
+			}
+			if priority > program.Priority.Get() {
+				return errors.Errorf("program %s: priority %d starts before dependency %s priority %d", program.Name, program.Priority.Get(), name, priority)
+			}
+		}
+	}
+	return nil
+}
+
+// AutoSpacePriorities assign priorities step, 2*step, ... in dependency order
+// Ties are broken by current priority then name, so output is deterministic
+// Gaps leave room to insert services later without renumbering
+//
+// AutoSpacePriorities 按依赖顺序分配优先级 step、2*step ...
+// 相同层级按当前优先级再按名称排序，因此结果是确定的
+// 间隔为之后插入服务留出空间，无需重新编号
+func AutoSpacePriorities(step int, programs ...*ProgramConfig) error {
+	must.True(step > 0)
+
+	byName := make(map[string]*ProgramConfig, len(programs))
+	for _, program := range programs {
+		byName[program.Name] = program
+	}
+	pending := make(map[string]int, len(programs))
+	dependents := make(map[string][]string, len(programs))
+	for _, program := range programs {
+		for _, name := range program.DependsOn {
+			if _, ok := byName[name]; ok {
+				pending[program.Name]++
+				dependents[name] = append(dependents[name], program.Name)
+			}
+		}
+	}
+
+	ready := make([]*ProgramConfig, 0, len(programs))
+	for _, program := range programs {
+		if pending[program.Name] == 0 {
+			ready = append(ready, program)
+		}
+	}
+	ordered := make([]*ProgramConfig, 0, len(programs))
+	for len(ready) > 0 {
+		slices.SortFunc(ready, func(a, b *ProgramConfig) int {
+			return cmp.Or(cmp.Compare(a.Priority.Get(), b.Priority.Get()), cmp.Compare(a.Name, b.Name))
+		})
+		next := ready[0]
+		ready = ready[1:]
+		ordered = append(ordered, next)
+		for _, name := range dependents[next.Name] {
+			pending[name]--
+			if pending[name] == 0 {
+				ready = append(ready, byName[name])
+			}
+		}
+	}
+	if len(ordered) < len(programs) {
+		return errors.Errorf("dependency cycle among %d programs", len(programs)-len(ordered))
+	}
+
+	for idx, program := range ordered {
+		program.Priority.Set((idx + 1) * step)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPriorityBand(t *testing.T) {
+	// Test priority bands keep tiers ordered
+	// 测试优先级区间保持层级顺序
+	gateway := supervisordkratos.NewProgramConfig("gateway", "/opt/gateway", "deploy", "/var/log/gateway").
+		WithPriorityBand(supervisordkratos.PriorityGateway, 10)
+	require.Equal(t, 210, gateway.Priority.Get())
+	require.Contains(t, supervisordkratos.GenerateProgramConfig(gateway), "priority        = 210\n")
+
+	band, ok := supervisordkratos.PriorityBandOf(gateway.Priority.Get())
+	require.True(t, ok)
+	require.Equal(t, supervisordkratos.PriorityGateway, band)
+
+	_, ok = supervisordkratos.PriorityBandOf(999)
+	require.False(t, ok)
+
+	require.Panics(t, func() {
+		gateway.WithPriorityBand(supervisordkratos.PriorityWorker, supervisordkratos.PriorityBandWidth)
+	})
+}
+
+func TestDependencyPriorityConflict(t *testing.T) {
+	// Test dependencies sharing or exceeding dependent priority fail group build
+	// 测试依赖与依赖方优先级相同或更高时组构建失败
+	registry := supervisordkratos.NewProgramConfig("registry", "/opt/registry", "deploy", "/var/log/registry")
+	api := supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/api").
+		WithDependsOn("registry")
+
+	group := supervisordkratos.NewGroupConfig("cluster").AddProgram(registry).AddProgram(api)
+	_, err := supervisordkratos.BuildGroupConfig(group)
+	require.ErrorContains(t, err, "duplicates dependency registry")
+
+	registry.WithPriority(600)
+	api.WithPriority(500)
+	_, err = supervisordkratos.BuildGroupConfig(group)
+	require.ErrorContains(t, err, "starts before dependency registry")
+
+	api.WithPriority(700)
+	_, err = supervisordkratos.BuildGroupConfig(group)
+	require.NoError(t, err)
+}
+
+func TestAutoSpacePriorities(t *testing.T) {
+	// Test auto spacing follows dependencies then priority and name
+	// 测试自动间隔先遵循依赖，再按优先级和名称排序
+	worker := supervisordkratos.NewProgramConfig("worker", "/opt/worker", "deploy", "/var/log/worker").
+		WithDependsOn("api")
+	api := supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/api").
+		WithDependsOn("registry")
+	registry := supervisordkratos.NewProgramConfig("registry", "/opt/registry", "deploy", "/var/log/registry")
+	metrics := supervisordkratos.NewProgramConfig("metrics", "/opt/metrics", "deploy", "/var/log/metrics")
+
+	require.NoError(t, supervisordkratos.AutoSpacePriorities(100, worker, api, registry, metrics))
+	require.Equal(t, 100, metrics.Priority.Get())
+	require.Equal(t, 200, registry.Priority.Get())
+	require.Equal(t, 300, api.Priority.Get())
+	require.Equal(t, 400, worker.Priority.Get())
+
+	group := supervisordkratos.NewGroupConfig("cluster").AddProgram(worker).AddProgram(api).AddProgram(registry).AddProgram(metrics)
+	_, err := supervisordkratos.BuildGroupConfig(group)
+	require.NoError(t, err)
+
+	registry.WithDependsOn("worker")
+	require.ErrorContains(t, supervisordkratos.AutoSpacePriorities(100, worker, api, registry), "dependency cycle")
+}
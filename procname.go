@@ -0,0 +1,36 @@
+package supervisordkratos
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// processNumPattern %(process_num) expansion with python printf flags, like %(process_num)02d
+// processNumPattern 带 python printf 格式的 %(process_num) 展开，例如 %(process_num)02d
+var processNumPattern = regexp.MustCompile(`%\(process_num\)([-#0 +]*\d*)d`)
+
+// ProcessNames expand ProcessName of each instance, numbered from 0 like numprocs_start default
+// Errors when the template uses expansions only known on the host, like %(ENV_X)s
+//
+// ProcessNames 展开每个实例的 ProcessName，与 numprocs_start 默认值一致从 0 开始编号
+// 模板使用只有主机才知道的展开（例如 %(ENV_X)s）时返回错误
+func (p *ProgramConfig) ProcessNames() ([]string, error) {
+	// group_name of a program section is the program name, even for [group:x] members
+	// 程序段落中的 group_name 为程序名称，即使是 [group:x] 成员也是如此
+	template := strings.NewReplacer("%(program_name)s", p.Name, "%(group_name)s", p.Name).Replace(p.ProcessName.Get())
+
+	names := make([]string, 0, p.NumProcs.Get())
+	for num := range p.NumProcs.Get() {
+		name := processNumPattern.ReplaceAllStringFunc(template, func(match string) string {
+			return fmt.Sprintf("%"+processNumPattern.FindStringSubmatch(match)[1]+"d", num)
+		})
+		if strings.Contains(name, "%(") {
+			return nil, errors.Errorf("program %s: process_name %q cannot be expanded offline", p.Name, p.ProcessName.Get())
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
@@ -0,0 +1,27 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessNames(t *testing.T) {
+	// Test process_name expansion of each instance
+	// 测试每个实例的 process_name 展开
+	api := supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/api").
+		WithNumProcs(3).FixProcessName()
+
+	names, err := api.ProcessNames()
+	require.NoError(t, err)
+	require.Equal(t, []string{"api_00", "api_01", "api_02"}, names)
+
+	single := supervisordkratos.NewProgramConfig("worker", "/opt/worker", "deploy", "/var/log/worker")
+	names, err = single.ProcessNames()
+	require.NoError(t, err)
+	require.Equal(t, []string{"worker"}, names)
+
+	_, err = single.WithProcessName("%(ENV_ROLE)s").ProcessNames()
+	require.Error(t, err)
+}
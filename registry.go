@@ -0,0 +1,133 @@
+package supervisordkratos
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/yyle88/must"
+)
+
+// Registry named collection of programs and groups, rendered selectively
+// Names use section form: "program:x" and "group:x"
+//
+// Registry 程序和组的命名集合，可选择性渲染
+// 名称使用段落形式："program:x" 和 "group:x"
+type Registry struct {
+	programs []*ProgramConfig // Registered standalone programs // 已注册的独立程序
+	groups   []*GroupConfig   // Registered groups // 已注册的组
+}
+
+// NewRegistry create new blank Registry
+// 创建新的空 Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		programs: make([]*ProgramConfig, 0),
+		groups:   make([]*GroupConfig, 0),
+	}
+}
+
+// AddProgram register standalone program, name must be unique
+// 注册独立程序，名称必须唯一
+func (r *Registry) AddProgram(program *ProgramConfig) *Registry {
+	must.Full(program)
+	_, exists := r.LookupProgram(program.Name)
+	must.False(exists)
+	r.programs = append(r.programs, program)
+	return r
+}
+
+// AddGroup register group, name must be unique
+// 注册组，名称必须唯一
+func (r *Registry) AddGroup(group *GroupConfig) *Registry {
+	must.Full(group)
+	_, exists := r.LookupGroup(group.Name)
+	must.False(exists)
+	r.groups = append(r.groups, group)
+	return r
+}
+
+// LookupProgram find program registered directly or inside a registered group
+// LookupProgram 查找直接注册或在已注册组中的程序
+func (r *Registry) LookupProgram(name string) (*ProgramConfig, bool) {
+	for _, program := range r.programs {
+		if program.Name == name {
+			return program, true
+		}
+	}
+	for _, group := range r.groups {
+		for _, program := range slices.Concat(group.Programs, group.Standalone) {
+			if program.Name == name {
+				return program, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// LookupGroup find registered group with name
+// LookupGroup 根据名称查找已注册的组
+func (r *Registry) LookupGroup(name string) (*GroupConfig, bool) {
+	for _, group := range r.groups {
+		if group.Name == name {
+			return group, true
+		}
+	}
+	return nil, false
+}
+
+// Programs list every registered program, standalone ones first then group members
+// Programs 列出所有已注册的程序，独立程序在前，然后是组成员
+func (r *Registry) Programs() []*ProgramConfig {
+	programs := slices.Clone(r.programs)
+	for _, group := range r.groups {
+		programs = append(programs, slices.Concat(group.Programs, group.Standalone)...)
+	}
+	return programs
+}
+
+// resolvedOf program as rendered, group members get DefaultUser/UserOverrides applied
+// resolvedOf 渲染时的程序，组成员会应用 DefaultUser/UserOverrides
+func (r *Registry) resolvedOf(program *ProgramConfig) *ProgramConfig {
+	for _, group := range r.groups {
+		if slices.Contains(group.Programs, program) || slices.Contains(group.Standalone, program) {
+			return group.resolvedPrograms([]*ProgramConfig{program})[0]
+		}
+	}
+	return program
+}
+
+// Names list registered names in registration order, programs first
+// Names 按注册顺序列出已注册的名称，程序在前
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.programs)+len(r.groups))
+	for _, program := range r.programs {
+		names = append(names, "program:"+program.Name)
+	}
+	for _, group := range r.groups {
+		names = append(names, "group:"+group.Name)
+	}
+	return names
+}
+
+// Render generate config of one registered entry, e.g. "group:payments" or "program:api"
+// Render 生成单个已注册条目的配置，例如 "group:payments" 或 "program:api"
+func (r *Registry) Render(name string) (string, error) {
+	kind, value, ok := strings.Cut(name, ":")
+	if !ok {
+		return "", errors.Errorf("invalid name %q, want program:x or group:x", name)
+	}
+	switch kind {
+	case "program":
+		if program, ok := r.LookupProgram(value); ok {
+			return GenerateProgramConfig(r.resolvedOf(program)), nil
+		}
+	case "group":
+		if group, ok := r.LookupGroup(value); ok {
+			return GenerateGroupConfig(group), nil
+		}
+	default:
+		return "", errors.Errorf("invalid name %q, want program:x or group:x", name)
+	}
+	return "", errors.Errorf("%s not registered", name)
+}
@@ -0,0 +1,85 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryRender(t *testing.T) {
+	// Test registering programs/groups and rendering one slice
+	// 测试注册程序和组并渲染其中一部分
+	payments := supervisordkratos.NewGroupConfig("payments").
+		AddProgram(supervisordkratos.NewProgramConfig("payments-api", "/opt/payments-api", "deploy", "/var/log/payments"))
+	monitor := supervisordkratos.NewProgramConfig("monitor", "/opt/monitor", "deploy", "/var/log/monitor")
+
+	registry := supervisordkratos.NewRegistry().
+		AddProgram(monitor).
+		AddGroup(payments)
+	require.Equal(t, []string{"program:monitor", "group:payments"}, registry.Names())
+
+	content, err := registry.Render("group:payments")
+	require.NoError(t, err)
+	require.Equal(t, supervisordkratos.GenerateGroupConfig(payments), content)
+
+	// Programs inside groups are found too
+	// 组内的程序也能被找到
+	program, ok := registry.LookupProgram("payments-api")
+	require.True(t, ok)
+	content, err = registry.Render("program:payments-api")
+	require.NoError(t, err)
+	require.Equal(t, supervisordkratos.GenerateProgramConfig(program), content)
+
+	_, err = registry.Render("group:unknown")
+	require.Error(t, err)
+	_, err = registry.Render("payments")
+	require.Error(t, err)
+
+	require.Panics(t, func() {
+		registry.AddProgram(supervisordkratos.NewProgramConfig("monitor", "/opt/monitor", "deploy", "/var/log/monitor"))
+	})
+}
+
+func TestRegistryRenderDefaultUser(t *testing.T) {
+	// Test group members render with group DefaultUser and overrides applied
+	// 测试组成员渲染时应用组的 DefaultUser 和单独覆盖
+	api := supervisordkratos.NewProgramConfig("payments-api", "/opt/payments-api", "deploy", "/var/log/payments").
+		WithLabel("team", "payments")
+	worker := supervisordkratos.NewProgramConfig("payments-worker", "/opt/payments-worker", "deploy", "/var/log/payments").
+		WithLabel("team", "payments")
+	payments := supervisordkratos.NewGroupConfig("payments").
+		AddProgram(api).
+		AddStandaloneProgram(worker).
+		WithDefaultUser("payments").
+		WithUserOverride("payments-worker", "batch")
+
+	registry := supervisordkratos.NewRegistry().AddGroup(payments)
+
+	content, err := registry.Render("program:payments-api")
+	require.NoError(t, err)
+	t.Log(content)
+	require.Contains(t, content, "user            = payments\n")
+
+	content, err = registry.RenderSelected("team=payments")
+	require.NoError(t, err)
+	t.Log(content)
+
+	const expected = `[program:payments-api]
+user            = payments
+directory       = /opt/payments-api
+command         = /opt/payments-api/bin/payments-api
+stdout_logfile  = /var/log/payments/payments-api.log
+stderr_logfile  = /var/log/payments/payments-api.err
+
+[program:payments-worker]
+user            = batch
+directory       = /opt/payments-worker
+command         = /opt/payments-worker/bin/payments-worker
+stdout_logfile  = /var/log/payments/payments-worker.log
+stderr_logfile  = /var/log/payments/payments-worker.err
+`
+
+	require.Equal(t, expected, content)
+	require.Equal(t, "deploy", api.UserName)
+}
@@ -0,0 +1,31 @@
+package supervisordkratos
+
+import (
+	"strconv"
+
+	"github.com/yyle88/must"
+)
+
+// Resources expected CPU/memory budget of one program instance
+// Not enforced by supervisord, surfaced to capacity planning tools
+//
+// Resources 单个程序实例预期的 CPU/内存预算
+// supervisord 不会强制执行，仅提供给容量规划工具
+type Resources struct {
+	CPUMillis   int   // CPU in millicores, 1000 = one core // CPU 毫核，1000 = 一个核心
+	MemoryBytes int64 // Memory in bytes // 内存字节数
+}
+
+// Comment returns structured comment text, e.g. "resources: cpu_millis=500 memory_bytes=536870912"
+// Comment 返回结构化注释文本，例如 "resources: cpu_millis=500 memory_bytes=536870912"
+func (r *Resources) Comment() string {
+	return "resources: cpu_millis=" + strconv.Itoa(r.CPUMillis) + " memory_bytes=" + strconv.FormatInt(r.MemoryBytes, 10)
+}
+
+// WithResources set expected CPU millicores and memory bytes per instance
+// 设置每个实例预期的 CPU 毫核和内存字节数
+func (p *ProgramConfig) WithResources(cpuMillis int, memoryBytes int64) *ProgramConfig {
+	must.True(cpuMillis >= 0 && memoryBytes >= 0)
+	p.Resources.Set(&Resources{CPUMillis: cpuMillis, MemoryBytes: memoryBytes})
+	return p
+}
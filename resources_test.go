@@ -0,0 +1,36 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgramConfigWithResources(t *testing.T) {
+	// Test resource expectations rendered as structured comment
+	// 测试资源预期以结构化注释渲染
+	program := supervisordkratos.NewProgramConfig(
+		"api",
+		"/opt/api",
+		"deploy",
+		"/var/log/api",
+	).WithResources(500, 512<<20)
+
+	content := supervisordkratos.GenerateProgramConfig(program)
+	t.Log(content)
+
+	const expected = `; resources: cpu_millis=500 memory_bytes=536870912
+[program:api]
+user            = deploy
+directory       = /opt/api
+command         = /opt/api/bin/api
+stdout_logfile  = /var/log/api/api.log
+stderr_logfile  = /var/log/api/api.err
+`
+	require.Equal(t, expected, content)
+
+	require.Panics(t, func() {
+		program.WithResources(-1, 0)
+	})
+}
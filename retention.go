@@ -0,0 +1,94 @@
+package supervisordkratos
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/yyle88/must"
+)
+
+// LogRetentionPreset named log rotation settings shared across services
+// LogRetentionPreset 在服务之间共享的命名日志轮转设置
+type LogRetentionPreset string
+
+const (
+	LogRetentionDev   LogRetentionPreset = "dev"   // 10MB x 3 backups // 10MB x 3 个备份
+	LogRetentionProd  LogRetentionPreset = "prod"  // 200MB x 20 backups // 200MB x 20 个备份
+	LogRetentionAudit LogRetentionPreset = "audit" // 1GB x 50 backups // 1GB x 50 个备份
+)
+
+// LogRetention rotation size and backup count of one log file
+// LogRetention 单个日志文件的轮转大小和备份数量
+type LogRetention struct {
+	MaxBytes string // stdout/stderr_logfile_maxbytes // stdout/stderr_logfile_maxbytes
+	Backups  int    // stdout/stderr_logfile_backups // stdout/stderr_logfile_backups
+}
+
+// logRetentionPresets settings of each preset
+// logRetentionPresets 每个预设的设置
+var logRetentionPresets = map[LogRetentionPreset]*LogRetention{
+	LogRetentionDev:   {MaxBytes: "10MB", Backups: 3},
+	LogRetentionProd:  {MaxBytes: "200MB", Backups: 20},
+	LogRetentionAudit: {MaxBytes: "1GB", Backups: 50},
+}
+
+// WithLogRetentionPreset set log max bytes and backups from preset
+// WithLogRetentionPreset 根据预设设置日志最大字节数和备份数量
+func (p *ProgramConfig) WithLogRetentionPreset(preset LogRetentionPreset) *ProgramConfig {
+	retention, ok := logRetentionPresets[preset]
+	must.True(ok)
+	return p.WithLogMaxBytes(retention.MaxBytes).WithLogBackups(retention.Backups)
+}
+
+// WithMinLogRetention require programs to keep at least preset size and backups, e.g. LogRetentionProd in production
+// WithMinLogRetention 要求程序至少保留预设的大小和备份数量，例如生产环境使用 LogRetentionProd
+func (v *Validator) WithMinLogRetention(preset LogRetentionPreset) *Validator {
+	_, ok := logRetentionPresets[preset]
+	must.True(ok)
+	v.MinLogRetention = preset
+	return v
+}
+
+// checkLogRetention reports programs rotating logs smaller or keeping fewer backups than preset
+// maxbytes=0 turns rotation off, so nothing is ever dropped
+//
+// checkLogRetention 报告日志轮转大小或备份数量低于预设的程序
+// maxbytes=0 表示关闭轮转，因此不会丢弃任何内容
+func checkLogRetention(program *ProgramConfig, preset LogRetentionPreset) error {
+	minimum := logRetentionPresets[preset]
+	maxBytes, err := parseByteSize(program.LogMaxBytes.Get())
+	if err != nil {
+		return errors.WithMessagef(err, "program %s", program.Name)
+	}
+	if maxBytes == 0 {
+		return nil
+	}
+	minBytes, _ := parseByteSize(minimum.MaxBytes)
+	if maxBytes < minBytes || program.LogBackups.Get() < minimum.Backups {
+		return errors.Errorf("program %s: log retention %s x %d is below %s preset %s x %d", program.Name, program.LogMaxBytes.Get(), program.LogBackups.Get(), preset, minimum.MaxBytes, minimum.Backups)
+	}
+	return nil
+}
+
+// parseByteSize parse supervisord byte size like 50MB into bytes, KB/MB/GB are powers of 1024
+// parseByteSize 将 50MB 等 supervisord 字节大小解析为字节数，KB/MB/GB 为 1024 的幂
+func parseByteSize(value string) (int64, error) {
+	if err := checkByteSize(value); err != nil {
+		return 0, err
+	}
+	upper := strings.ToUpper(value)
+	multiplier := int64(1)
+	for idx, suffix := range []string{"KB", "MB", "GB"} {
+		if strings.HasSuffix(upper, suffix) {
+			multiplier = int64(1) << (10 * (idx + 1))
+			upper = strings.TrimSuffix(upper, suffix)
+			break
+		}
+	}
+	number, err := strconv.ParseInt(upper, 10, 64)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return number * multiplier, nil
+}
@@ -0,0 +1,48 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithLogRetentionPreset(t *testing.T) {
+	// Test preset sets both rotation size and backups
+	// 测试预设同时设置轮转大小和备份数量
+	program := supervisordkratos.NewProgramConfig(
+		"audit",
+		"/opt/audit",
+		"deploy",
+		"/var/log/audit",
+	).WithLogRetentionPreset(supervisordkratos.LogRetentionAudit)
+
+	content := supervisordkratos.GenerateProgramConfig(program)
+	t.Log(content)
+	require.Contains(t, content, "stdout_logfile_maxbytes = 1GB\n")
+	require.Contains(t, content, "stdout_logfile_backups = 50\n")
+
+	require.Panics(t, func() {
+		program.WithLogRetentionPreset("staging")
+	})
+}
+
+func TestValidateMinLogRetention(t *testing.T) {
+	// Test production policy rejects retention below prod preset
+	// 测试生产策略拒绝低于 prod 预设的日志保留
+	validator := supervisordkratos.NewValidator().WithMinLogRetention(supervisordkratos.LogRetentionProd)
+
+	newProgram := func() *supervisordkratos.ProgramConfig {
+		return supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/api")
+	}
+
+	require.ErrorContains(t, validator.ValidateProgram(newProgram()), "below prod preset")
+	require.ErrorContains(t, validator.ValidateProgram(newProgram().WithLogRetentionPreset(supervisordkratos.LogRetentionDev)), "below prod preset")
+	require.NoError(t, validator.ValidateProgram(newProgram().WithLogRetentionPreset(supervisordkratos.LogRetentionProd)))
+	require.NoError(t, validator.ValidateProgram(newProgram().WithLogRetentionPreset(supervisordkratos.LogRetentionAudit)))
+	require.NoError(t, validator.ValidateProgram(newProgram().WithLogMaxBytes("0")))
+
+	// Policy is off by default
+	// 默认不启用该策略
+	require.NoError(t, supervisordkratos.ValidateProgramConfig(newProgram()))
+}
@@ -0,0 +1,70 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSandboxBwrap(t *testing.T) {
+	// Test command wrapped in bubblewrap with bind mounts
+	// 测试命令使用 bubblewrap 包装并带有绑定挂载
+	program := supervisordkratos.NewProgramConfig(
+		"api",
+		"/opt/api",
+		"deploy",
+		"/var/log/api",
+	).WithSandbox(supervisordkratos.NewSandbox(supervisordkratos.SandboxBwrap).
+		WithReadOnlyBind("/usr", "/usr").
+		WithReadOnlyBind("/opt/api", "/opt/api").
+		WithBind("/var/log/api", "/var/log/api"))
+
+	content, err := supervisordkratos.BuildProgramConfig(program)
+	require.NoError(t, err)
+	t.Log(content)
+
+	const expected = `[program:api]
+user            = deploy
+directory       = /opt/api
+command         = bwrap --die-with-parent --unshare-all --share-net --proc /proc --dev /dev --ro-bind /usr /usr --ro-bind /opt/api /opt/api --bind /var/log/api /var/log/api -- /opt/api/bin/api
+stdout_logfile  = /var/log/api/api.log
+stderr_logfile  = /var/log/api/api.err
+`
+
+	require.Equal(t, expected, content)
+}
+
+func TestSandboxUnshare(t *testing.T) {
+	// Test unshare sandbox with no-new-privileges and rejected bind mounts
+	// 测试 unshare 沙箱的 no-new-privileges 以及被拒绝的绑定挂载
+	sandbox := supervisordkratos.NewSandbox(supervisordkratos.SandboxUnshare)
+	require.Equal(t, "setpriv --no-new-privs unshare --user --map-root-user --mount --pid --ipc --uts --fork --kill-child -- /opt/api/bin/api", sandbox.Wrap("/opt/api/bin/api"))
+
+	require.Equal(t, "unshare --user --map-root-user --mount --pid --ipc --uts --fork --kill-child -- /opt/api/bin/api", sandbox.WithNoNewPrivileges(false).Wrap("/opt/api/bin/api"))
+
+	program := supervisordkratos.NewProgramConfig(
+		"api",
+		"/opt/api",
+		"deploy",
+		"/var/log/api",
+	).WithSandbox(sandbox.WithBind("/data", "/data"))
+	require.Error(t, supervisordkratos.ValidateProgramConfig(program))
+}
+
+func TestSandboxUnshareNonRootUser(t *testing.T) {
+	// Test rendered unshare command creates a user namespace, non-root user= cannot unshare otherwise
+	// 测试渲染的 unshare 命令创建用户命名空间，否则非 root 的 user= 无法执行 unshare
+	program := supervisordkratos.NewProgramConfig(
+		"api",
+		"/opt/api",
+		"deploy",
+		"/var/log/api",
+	).WithSandbox(supervisordkratos.NewSandbox(supervisordkratos.SandboxUnshare).WithNoNewPrivileges(false))
+
+	content, err := supervisordkratos.BuildProgramConfig(program)
+	require.NoError(t, err)
+	t.Log(content)
+	require.Contains(t, content, "user            = deploy\n")
+	require.Contains(t, content, "command         = unshare --user --map-root-user --mount --pid --ipc --uts --fork --kill-child -- /opt/api/bin/api\n")
+}
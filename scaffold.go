@@ -0,0 +1,51 @@
+package supervisordkratos
+
+import (
+	"strconv"
+
+	"github.com/yyle88/must"
+	"github.com/yyle88/printgo"
+)
+
+// Scaffold generate ready-to-edit Go main file configuring service name with this package
+// Covers program, group, validated build and writing conf.d file, paths follow Kratos layout
+//
+// Scaffold 生成可直接编辑的 Go main 文件，使用本包配置名为 name 的服务
+// 包括程序、组、校验构建和写入 conf.d 文件，路径遵循 Kratos 布局
+func Scaffold(name string) string {
+	must.Nice(name)
+	quoted := strconv.Quote(name)
+
+	ptx := printgo.NewPTX()
+	ptx.Println("package main")
+	ptx.Println()
+	ptx.Println("import (")
+	ptx.Println("\t\"os\"")
+	ptx.Println()
+	ptx.Println("\t\"github.com/orzkratos/supervisordkratos\"")
+	ptx.Println(")")
+	ptx.Println()
+	ptx.Println("func main() {")
+	ptx.Println("\tprogram := supervisordkratos.NewProgramConfig(")
+	ptx.Println("\t\t" + quoted + ",")
+	ptx.Println("\t\t" + strconv.Quote("/opt/"+name) + ",")
+	ptx.Println("\t\t\"deploy\",")
+	ptx.Println("\t\t" + strconv.Quote("/var/log/"+name) + ",")
+	ptx.Println("\t).")
+	ptx.Println("\t\tWithStartSecs(supervisordkratos.KratosBootstrapSecs).")
+	ptx.Println("\t\tWithPriorityBand(supervisordkratos.PriorityService, 0)")
+	ptx.Println()
+	ptx.Println("\tgroup := supervisordkratos.NewGroupConfig(" + quoted + ").")
+	ptx.Println("\t\tAddProgram(program)")
+	ptx.Println()
+	ptx.Println("\tcontent, err := supervisordkratos.BuildGroupConfig(group)")
+	ptx.Println("\tif err != nil {")
+	ptx.Println("\t\tpanic(err)")
+	ptx.Println("\t}")
+	ptx.Println("\tif err := os.WriteFile(" + strconv.Quote("/etc/supervisor/conf.d/"+name+".conf") + ", []byte(content), 0o644); err != nil {")
+	ptx.Println("\t\tpanic(err)")
+	ptx.Println("\t}")
+	ptx.Println("\t// Apply with: supervisorctl reread && supervisorctl update " + name)
+	ptx.Println("}")
+	return ptx.String()
+}
@@ -0,0 +1,25 @@
+package supervisordkratos_test
+
+import (
+	"go/format"
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaffold(t *testing.T) {
+	// Test scaffold output is gofmt-clean Go source
+	// 测试脚手架输出是符合 gofmt 的 Go 源码
+	content := supervisordkratos.Scaffold("payments-api")
+	t.Log(content)
+
+	formatted, err := format.Source([]byte(content))
+	require.NoError(t, err)
+	require.Equal(t, content, string(formatted))
+	require.Contains(t, content, `"/etc/supervisor/conf.d/payments-api.conf"`)
+
+	require.Panics(t, func() {
+		supervisordkratos.Scaffold("")
+	})
+}
@@ -0,0 +1,44 @@
+package supervisordkratos
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yyle88/must"
+)
+
+// WithSchedule run command every interval inside a long-running /bin/sh sleep loop
+// supervisord has no scheduler, the loop keeps program RUNNING between rounds,
+// a failing round does not stop the loop, Linux targets only
+// Sets stopasgroup/killasgroup, the loop shell does not forward stop signals to the running round
+//
+// WithSchedule 在长期运行的 /bin/sh 睡眠循环中每隔 interval 运行命令
+// supervisord 没有调度器，循环使程序在各轮之间保持 RUNNING，
+// 单轮失败不会停止循环，仅支持 Linux 目标
+// 会设置 stopasgroup/killasgroup，循环 shell 不会将停止信号转发给正在运行的一轮
+func (p *ProgramConfig) WithSchedule(interval time.Duration) *ProgramConfig {
+	must.True(interval >= time.Second)
+	p.Schedule.Set(interval)
+	return p.WithStopAsGroup(true).WithKillAsGroup(true)
+}
+
+// NewScheduledJob create cron-worker program running its binary every interval
+// Stop signals reach the running round through stopasgroup/killasgroup
+//
+// NewScheduledJob 创建每隔 interval 运行其二进制的 cron-worker 程序
+// 停止信号通过 stopasgroup/killasgroup 传递给正在运行的一轮
+func NewScheduledJob(name string, root string, userName string, slogRoot string, interval time.Duration) *ProgramConfig {
+	return NewFromArchetype(name, ArchetypeCronWorker, root, userName, slogRoot).WithSchedule(interval)
+}
+
+// scheduleLoopEscaper escapes characters supervisord shlex treats specially inside double quotes
+// scheduleLoopEscaper 转义 supervisord shlex 在双引号内特殊处理的字符
+var scheduleLoopEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// scheduleLoop wraps command in sh loop sleeping interval seconds after each round
+// scheduleLoop 将命令包装在 sh 循环中，每轮结束后睡眠 interval 秒
+func scheduleLoop(command string, interval time.Duration) string {
+	seconds := strconv.Itoa(int(interval / time.Second))
+	return `/bin/sh -c "while :; do ` + scheduleLoopEscaper.Replace(command) + `; sleep ` + seconds + `; done"`
+}
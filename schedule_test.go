@@ -0,0 +1,67 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewScheduledJob(t *testing.T) {
+	// Test scheduled job wraps binary in sleep loop
+	// 测试定时任务将二进制包装在睡眠循环中
+	job := supervisordkratos.NewScheduledJob("cleanup", "/opt/cleanup", "deploy", "/var/log/cleanup", time.Hour)
+
+	content := supervisordkratos.GenerateProgramConfig(job)
+	t.Log(content)
+
+	const expected = `[program:cleanup]
+user            = deploy
+directory       = /opt/cleanup
+command         = /bin/sh -c "while :; do /opt/cleanup/bin/cleanup; sleep 3600; done"
+autorestart     = true
+stdout_logfile  = /var/log/cleanup/cleanup.log
+stderr_logfile  = /var/log/cleanup/cleanup.err
+redirect_stderr = true
+stopasgroup     = true
+stopwaitsecs    = 60
+killasgroup     = true
+priority        = 800
+`
+	require.Equal(t, expected, content)
+	require.NoError(t, supervisordkratos.ValidateProgramConfig(job))
+	require.NoError(t, supervisordkratos.Simulate(content))
+
+	windowsJob := supervisordkratos.NewScheduledJob("cleanup", `C:\jobs\cleanup`, "deploy", `C:\logs`, time.Hour).
+		WithTargetOS(supervisordkratos.TargetWindows)
+	require.ErrorContains(t, supervisordkratos.ValidateProgramConfig(windowsJob), "schedule")
+
+	require.Panics(t, func() {
+		job.WithSchedule(time.Millisecond)
+	})
+}
+
+func TestScheduleStopsRoundAsGroup(t *testing.T) {
+	// Test schedule turns on group signals and rejects turning them off
+	// 测试定时任务开启按组发送信号，并拒绝将其关闭
+	job := supervisordkratos.NewProgramConfig("cleanup", "/opt/cleanup", "deploy", "/var/log/cleanup").
+		WithSchedule(time.Minute)
+	require.True(t, job.StopAsGroup.Get())
+	require.True(t, job.KillAsGroup.Get())
+	require.NoError(t, supervisordkratos.ValidateProgramConfig(job))
+
+	require.ErrorContains(t, supervisordkratos.ValidateProgramConfig(job.WithStopAsGroup(false)), "orphans the running round")
+}
+
+func TestScheduleEscapesQuotes(t *testing.T) {
+	// Test quotes in command survive the double-quoted loop
+	// 测试命令中的引号在双引号循环中得以保留
+	job := supervisordkratos.NewProgramConfig("cleanup", "/opt/cleanup", "deploy", "/var/log/cleanup").
+		WithSandbox(supervisordkratos.NewSandbox(supervisordkratos.SandboxBwrap).WithBind(`/srv/"data"`, "/data")).
+		WithSchedule(time.Minute)
+
+	content := supervisordkratos.GenerateProgramConfig(job)
+	t.Log(content)
+	require.Contains(t, content, `--bind /srv/\"data\" /data -- /opt/cleanup/bin/cleanup; sleep 60; done"`)
+}
@@ -0,0 +1,351 @@
+package supervisordkratos
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// optionRule checks one option value the way supervisord datatypes do
+// optionRule 按照 supervisord datatypes 的方式检查单个选项值
+type optionRule func(value string) error
+
+// programOptionRules supervisord [program:x] options and their value types
+// Options absent here are ignored by supervisord, so Simulate ignores them too
+//
+// programOptionRules supervisord [program:x] 选项及其值类型
+// 不在此处的选项会被 supervisord 忽略，因此 Simulate 也忽略它们
+var programOptionRules = map[string]optionRule{
+	"command":                 checkExpansions,
+	"process_name":            checkExpansions,
+	"directory":               checkExpansions,
+	"environment":             checkEnvironment,
+	"user":                    checkNonBlank,
+	"umask":                   checkOctal,
+	"numprocs":                checkInteger,
+	"numprocs_start":          checkInteger,
+	"priority":                checkInteger,
+	"autostart":               checkBoolean,
+	"autorestart":             checkAutoRestart,
+	"startsecs":               checkInteger,
+	"startretries":            checkInteger,
+	"exitcodes":               checkExitCodes,
+	"stopsignal":              checkSignal,
+	"stopwaitsecs":            checkInteger,
+	"stopasgroup":             checkBoolean,
+	"killasgroup":             checkBoolean,
+	"redirect_stderr":         checkBoolean,
+	"stdout_logfile":          checkExpansions,
+	"stdout_logfile_maxbytes": checkByteSize,
+	"stdout_logfile_backups":  checkInteger,
+	"stdout_capture_maxbytes": checkByteSize,
+	"stdout_events_enabled":   checkBoolean,
+	"stdout_syslog":           checkBoolean,
+	"stderr_logfile":          checkExpansions,
+	"stderr_logfile_maxbytes": checkByteSize,
+	"stderr_logfile_backups":  checkInteger,
+	"stderr_capture_maxbytes": checkByteSize,
+	"stderr_events_enabled":   checkBoolean,
+	"stderr_syslog":           checkBoolean,
+}
+
+// expansionNames names supervisord offers to %(name)s expressions, besides ENV_*
+// expansionNames supervisord 为 %(name)s 表达式提供的名称，ENV_* 之外
+var expansionNames = map[string]bool{
+	"program_name":   true,
+	"process_num":    true,
+	"group_name":     true,
+	"host_node_name": true,
+	"numprocs":       true,
+	"here":           true,
+}
+
+// signalNumbers Linux signals of Python's signal module, which supervisord resolves names and numbers against
+// Names are accepted with or without SIG prefix, numbers only when some name maps to them
+//
+// signalNumbers Python signal 模块中的 Linux 信号，supervisord 依此解析名称和编号
+// 名称可带或不带 SIG 前缀，编号只有在有名称对应时才被接受
+var signalNumbers = map[string]int{
+	"HUP": 1, "INT": 2, "QUIT": 3, "ILL": 4, "TRAP": 5, "ABRT": 6, "IOT": 6, "BUS": 7,
+	"FPE": 8, "KILL": 9, "USR1": 10, "SEGV": 11, "USR2": 12, "PIPE": 13, "ALRM": 14, "TERM": 15,
+	"STKFLT": 16, "CHLD": 17, "CLD": 17, "CONT": 18, "STOP": 19, "TSTP": 20, "TTIN": 21, "TTOU": 22,
+	"URG": 23, "XCPU": 24, "XFSZ": 25, "VTALRM": 26, "PROF": 27, "WINCH": 28, "IO": 29, "POLL": 29,
+	"PWR": 30, "SYS": 31, "RTMIN": 34, "RTMAX": 64,
+}
+
+// byteSizePattern integer with optional KB/MB/GB suffix, case-insensitive
+// byteSizePattern 带可选 KB/MB/GB 后缀的整数，不区分大小写
+var byteSizePattern = regexp.MustCompile(`(?i)^\d+(KB|MB|GB)?$`)
+
+// Simulate predicts whether supervisord accepts configText without running it
+// Checks option types, %(name)s expansions, numprocs/process_name rule and group members,
+// returns the first error found
+//
+// Simulate 在不运行 supervisord 的情况下预测其是否接受 configText
+// 检查选项类型、%(name)s 展开、numprocs/process_name 规则和组成员，返回发现的第一个错误
+func Simulate(configText string) error {
+	document, err := ParseDocument(configText)
+	if err != nil {
+		return err
+	}
+	document = mergeSections(document)
+	for _, section := range document.Sections {
+		kind, _, _ := strings.Cut(section.Name, ":")
+		switch kind {
+		case "program":
+			err = simulateProgram(section)
+		case "eventlistener":
+			err = simulateEventListener(section)
+		case "group":
+			err = simulateGroup(document, section)
+		}
+		if err != nil {
+			return errors.WithMessagef(err, "[%s]", section.Name)
+		}
+	}
+	return nil
+}
+
+// simulateProgram checks one [program:x] section
+// simulateProgram 检查单个 [program:x] 段落
+func simulateProgram(section *Section) error {
+	for _, entry := range section.Entries {
+		if rule, ok := programOptionRules[entry.Key]; ok {
+			if err := rule(entry.Value); err != nil {
+				return errors.WithMessagef(err, "%s", entry.Key)
+			}
+		}
+	}
+	if _, ok := section.Lookup("command"); !ok {
+		return errors.New("section does not specify a command")
+	}
+	numProcs := 1
+	if value, ok := section.Lookup("numprocs"); ok {
+		numProcs, _ = strconv.Atoi(value)
+	}
+	processName, _ := section.Lookup("process_name")
+	if numProcs > 1 && !strings.Contains(processName, "%(process_num)") {
+		return errors.New("%(process_num) must be present within process_name when numprocs > 1")
+	}
+	// killasgroup defaults to stopasgroup, an explicit false contradicts it
+	// killasgroup 默认跟随 stopasgroup，显式 false 与之矛盾
+	stopAsGroup, _ := section.Lookup("stopasgroup")
+	killAsGroup, ok := section.Lookup("killasgroup")
+	if ok && isTruthy(stopAsGroup) && !isTruthy(killAsGroup) {
+		return errors.New("Cannot set stopasgroup=true and killasgroup=false")
+	}
+	return nil
+}
+
+// simulateEventListener checks one [eventlistener:x] section, program rules plus listener ones
+// simulateEventListener 检查单个 [eventlistener:x] 段落，程序规则加上监听器规则
+func simulateEventListener(section *Section) error {
+	if err := simulateProgram(section); err != nil {
+		return err
+	}
+	if _, ok := section.Lookup("events"); !ok {
+		return errors.New("section does not specify events")
+	}
+	if value, _ := section.Lookup("redirect_stderr"); isTruthy(value) {
+		return errors.New("redirect_stderr=true is not allowed, it would interfere with the eventlistener protocol")
+	}
+	return nil
+}
+
+// simulateGroup checks one [group:x] section, members must be program sections in document
+// simulateGroup 检查单个 [group:x] 段落，成员必须是文档中的程序段落
+func simulateGroup(document *Document, section *Section) error {
+	if value, ok := section.Lookup("priority"); ok {
+		if err := checkInteger(value); err != nil {
+			return errors.WithMessage(err, "priority")
+		}
+	}
+	programs, ok := section.Lookup("programs")
+	if !ok {
+		return errors.New("section does not specify programs")
+	}
+	for _, name := range strings.Split(programs, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := document.Lookup("program:" + name); !ok {
+			return errors.Errorf("program section program:%s does not exist", name)
+		}
+	}
+	return nil
+}
+
+// mergeSections folds repeated sections and options, later values win
+// supervisord reads with strict=False ConfigParser, which accepts duplicates this way
+//
+// mergeSections 合并重复的段落和选项，后出现的值生效
+// supervisord 使用 strict=False 的 ConfigParser 读取，以这种方式接受重复项
+func mergeSections(document *Document) *Document {
+	merged := NewDocument()
+	for _, section := range document.Sections {
+		target, ok := merged.Lookup(section.Name)
+		if !ok {
+			target = NewSection(section.Name)
+			merged.Add(target)
+		}
+		for _, entry := range section.Entries {
+			target.Set(entry.Key, entry.Value)
+		}
+	}
+	return merged
+}
+
+// checkExpansions validates Python %-format expressions against supervisord names
+// checkExpansions 根据 supervisord 名称校验 Python %-格式表达式
+func checkExpansions(value string) error {
+	for idx := 0; idx < len(value); idx++ {
+		if value[idx] != '%' {
+			continue
+		}
+		rest := value[idx+1:]
+		if strings.HasPrefix(rest, "%") {
+			idx++
+			continue
+		}
+		if !strings.HasPrefix(rest, "(") {
+			return errors.Errorf("bare %% in %q, use %%%% for literal", value)
+		}
+		end := strings.IndexByte(rest, ')')
+		if end < 0 {
+			return errors.Errorf("unclosed expansion in %q", value)
+		}
+		name := rest[1:end]
+		if !expansionNames[name] && !strings.HasPrefix(name, "ENV_") {
+			return errors.Errorf("unknown expansion %%(%s) in %q", name, value)
+		}
+		// Skip flags, width and precision, then require a conversion type
+		// 跳过标志、宽度和精度，然后要求转换类型
+		pos := end + 1
+		for pos < len(rest) && strings.IndexByte("#0- +.0123456789", rest[pos]) >= 0 {
+			pos++
+		}
+		if pos >= len(rest) || strings.IndexByte("diouxXeEfFgGcrsa", rest[pos]) < 0 {
+			return errors.Errorf("expansion %%(%s) lacks conversion type in %q", name, value)
+		}
+		idx += 1 + pos
+	}
+	return nil
+}
+
+// checkEnvironment validates KEY=value pairs and expansions inside values
+// checkEnvironment 校验 KEY=value 键值对及值中的展开
+func checkEnvironment(value string) error {
+	pairs, err := splitSsMap(value, ",")
+	if err != nil {
+		return err
+	}
+	for _, item := range pairs {
+		if err := checkExpansions(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkNonBlank rejects blank value
+// checkNonBlank 拒绝空值
+func checkNonBlank(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return errors.New("blank value")
+	}
+	return nil
+}
+
+// checkInteger validates decimal integer
+// checkInteger 校验十进制整数
+func checkInteger(value string) error {
+	if _, err := strconv.Atoi(value); err != nil {
+		return errors.Errorf("invalid integer %q", value)
+	}
+	return nil
+}
+
+// checkOctal validates octal umask like 022
+// checkOctal 校验八进制 umask，例如 022
+func checkOctal(value string) error {
+	if _, err := strconv.ParseUint(value, 8, 32); err != nil {
+		return errors.Errorf("invalid octal %q", value)
+	}
+	return nil
+}
+
+// checkBoolean validates supervisord boolean spellings
+// checkBoolean 校验 supervisord 布尔值写法
+func checkBoolean(value string) error {
+	switch strings.ToLower(value) {
+	case "true", "yes", "on", "1", "false", "no", "off", "0":
+		return nil
+	}
+	return errors.Errorf("invalid boolean %q", value)
+}
+
+// isTruthy checks boolean text supervisord reads as true
+// isTruthy 检查 supervisord 读取为 true 的布尔文本
+func isTruthy(value string) bool {
+	switch strings.ToLower(value) {
+	case "true", "yes", "on", "1":
+		return true
+	}
+	return false
+}
+
+// checkAutoRestart validates boolean or "unexpected"
+// checkAutoRestart 校验布尔值或 "unexpected"
+func checkAutoRestart(value string) error {
+	if strings.ToLower(value) == "unexpected" {
+		return nil
+	}
+	if err := checkBoolean(value); err != nil {
+		return errors.Errorf("invalid autorestart %q, want true, false or unexpected", value)
+	}
+	return nil
+}
+
+// checkExitCodes validates comma-separated exit codes in 0..255
+// checkExitCodes 校验逗号分隔的 0..255 退出码
+func checkExitCodes(value string) error {
+	codes, err := splitInts(value, ",")
+	if err != nil {
+		return errors.Errorf("invalid exit codes %q", value)
+	}
+	for _, code := range codes {
+		if code < 0 || code > 255 {
+			return errors.Errorf("exit code %d out of range 0..255", code)
+		}
+	}
+	return nil
+}
+
+// checkSignal validates signal name or number
+// checkSignal 校验信号名称或编号
+func checkSignal(value string) error {
+	if num, err := strconv.Atoi(value); err == nil {
+		for _, known := range signalNumbers {
+			if known == num {
+				return nil
+			}
+		}
+		return errors.Errorf("invalid signal number %q", value)
+	}
+	if _, ok := signalNumbers[strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(value)), "SIG")]; ok {
+		return nil
+	}
+	return errors.Errorf("invalid signal %q", value)
+}
+
+// checkByteSize validates size like 50MB
+// checkByteSize 校验大小，例如 50MB
+func checkByteSize(value string) error {
+	if !byteSizePattern.MatchString(value) {
+		return errors.Errorf("invalid byte size %q", value)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateGenerated(t *testing.T) {
+	// Test generated group config is accepted
+	// 测试生成的组配置被接受
+	group := supervisordkratos.NewGroupConfig("payments").
+		AddProgram(supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/api").
+			WithNumProcs(2).
+			FixProcessName().
+			WithEnvironment(map[string]string{"HOME": "/home/%(ENV_USER)s"})).
+		AddProgram(supervisordkratos.NewProgramConfig("worker", "/opt/worker", "deploy", "/var/log/worker").
+			WithStopSignal("QUIT"))
+
+	content := supervisordkratos.GenerateGroupConfig(group)
+	t.Log(content)
+
+	require.NoError(t, supervisordkratos.Simulate(content))
+}
+
+func TestSimulateRejects(t *testing.T) {
+	// Test errors supervisord would raise at startup
+	// 测试 supervisord 启动时会报出的错误
+	for _, text := range []string{
+		"[program:api]\ncommand=/opt/api/bin/api\nstartsecs=soon\n",
+		"[program:api]\ncommand=/opt/api/bin/api\nautorestart=sometimes\n",
+		"[program:api]\ncommand=/opt/api/bin/api\nstopsignal=BOOM\n",
+		"[program:api]\ncommand=/opt/api/bin/api\nstopsignal=40\n",
+		"[program:api]\ncommand=/opt/api/bin/api\nexitcodes=0,300\n",
+		"[program:api]\ncommand=/opt/api/bin/api\nstdout_logfile_maxbytes=50XB\n",
+		"[program:api]\ncommand=/opt/api/bin/api --name %(service)s\n",
+		"[program:api]\ncommand=/opt/api/bin/api --rate 50%\n",
+		"[program:api]\ncommand=/opt/api/bin/api\nnumprocs=2\n",
+		"[program:api]\ndirectory=/opt/api\n",
+		"[group:payments]\nprograms=api\n",
+		"[program:api]\ncommand=/opt/api/bin/api\n\n[program:api]\nstartsecs=soon\n",
+	} {
+		err := supervisordkratos.Simulate(text)
+		require.Error(t, err, text)
+		t.Log(err)
+	}
+}
+
+func TestSimulateMergesDuplicates(t *testing.T) {
+	// Test repeated sections and options merge with later values winning, like supervisord
+	// 测试重复的段落和选项像 supervisord 一样合并，后出现的值生效
+	for _, text := range []string{
+		"[program:api]\ncommand=/opt/api/bin/api\nstartsecs=soon\nstartsecs=5\n",
+		"[program:api]\ncommand=/opt/api/bin/api\n\n[program:api]\nstartsecs=5\n",
+		"[program:api]\nstartsecs=5\n\n[program:api]\ncommand=/opt/api/bin/api\n",
+		"[program:api]\ncommand=/opt/api/bin/api\n\n[group:payments]\nprograms=\n",
+	} {
+		require.NoError(t, supervisordkratos.Simulate(text), text)
+	}
+}
+
+func TestSimulateSignals(t *testing.T) {
+	// Test every signal name of supervisord's table is accepted, with or without SIG prefix
+	// 测试 supervisord 信号表中的每个名称都被接受，可带或不带 SIG 前缀
+	for _, signal := range []string{"TERM", "TSTP", "TTIN", "TTOU", "WINCH", "URG", "SIGPWR", "sigusr2", "RTMIN", "15", "64"} {
+		require.NoError(t, supervisordkratos.Simulate("[program:api]\ncommand=/opt/api/bin/api\nstopsignal="+signal+"\n"), signal)
+	}
+}
@@ -0,0 +1,174 @@
+package supervisordkratos
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/yyle88/must"
+)
+
+// snapshotManifestName manifest entry name inside snapshot tarball
+// snapshotManifestName 快照 tar 包中清单条目的名称
+const snapshotManifestName = "manifest.json"
+
+// snapshotConfDir directory holding conf files inside snapshot tarball
+// snapshotConfDir 快照 tar 包中存放 conf 文件的目录
+const snapshotConfDir = "conf"
+
+// SnapshotManifest what a host snapshot holds
+// SnapshotManifest 主机快照包含的内容
+type SnapshotManifest struct {
+	CreatedAt time.Time               `json:"created_at"` // Snapshot time // 快照时间
+	Files     []string                `json:"files"`      // Conf file names, sorted // conf 文件名称，已排序
+	States    map[string]ProcessState `json:"states"`     // Process state by group:name // 按 group:name 记录的进程状态
+}
+
+// SnapshotHost write gzip tarball of *.conf files in confDir plus process states from `supervisorctl status` output
+// Querying supervisord is left to the caller, pass its status output as statusOutput
+//
+// SnapshotHost 将 confDir 中的 *.conf 文件和 `supervisorctl status` 输出中的进程状态写入 gzip tar 包
+// 查询 supervisord 由调用方负责，将其状态输出作为 statusOutput 传入
+func SnapshotHost(w io.Writer, confDir string, statusOutput string, now time.Time) (*SnapshotManifest, error) {
+	statuses, err := ParseSupervisorctlStatus(statusOutput)
+	if err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(confDir, "*.conf"))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	manifest := &SnapshotManifest{
+		CreatedAt: now,
+		Files:     make([]string, 0, len(paths)),
+		States:    make(map[string]ProcessState, len(statuses)),
+	}
+	for _, status := range statuses {
+		manifest.States[status.FullName()] = status.State
+	}
+	for _, item := range paths {
+		manifest.Files = append(manifest.Files, filepath.Base(item))
+	}
+	slices.Sort(manifest.Files)
+
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := writeTarFile(tarWriter, snapshotManifestName, data, now); err != nil {
+		return nil, err
+	}
+	for _, name := range manifest.Files {
+		content, err := os.ReadFile(filepath.Join(confDir, name))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if err := writeTarFile(tarWriter, path.Join(snapshotConfDir, name), content, now); err != nil {
+			return nil, err
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return manifest, nil
+}
+
+// RestoreHost write conf files of snapshot back into confDir and return supervisorctl commands re-applying states
+// Processes RUNNING at snapshot time are started, STOPPED ones are stopped, other states are left to autostart
+//
+// RestoreHost 将快照中的 conf 文件写回 confDir，并返回重新应用状态的 supervisorctl 命令
+// 快照时 RUNNING 的进程会被启动，STOPPED 的进程会被停止，其他状态交给 autostart 处理
+func RestoreHost(r io.Reader, confDir string, supervisorctl string) ([]string, error) {
+	must.Nice(supervisorctl)
+
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer func() { _ = gzipReader.Close() }()
+
+	var manifest *SnapshotManifest
+	files := make(map[string][]byte)
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		data, err := io.ReadAll(tarReader)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		switch {
+		case header.Name == snapshotManifestName:
+			manifest = &SnapshotManifest{}
+			if err := json.Unmarshal(data, manifest); err != nil {
+				return nil, errors.WithStack(err)
+			}
+		case path.Dir(header.Name) == snapshotConfDir && strings.HasSuffix(header.Name, ".conf"):
+			files[path.Base(header.Name)] = data
+		default:
+			return nil, errors.Errorf("unexpected snapshot entry %q", header.Name)
+		}
+	}
+	if manifest == nil {
+		return nil, errors.New("snapshot has no manifest")
+	}
+	if !slices.Equal(manifest.Files, slices.Sorted(maps.Keys(files))) {
+		return nil, errors.Errorf("snapshot files %v do not match manifest %v", slices.Sorted(maps.Keys(files)), manifest.Files)
+	}
+
+	for _, name := range manifest.Files {
+		if err := os.WriteFile(filepath.Join(confDir, name), files[name], 0o644); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	commands := []string{
+		supervisorctl + " reread",
+		supervisorctl + " update",
+	}
+	for _, name := range slices.Sorted(maps.Keys(manifest.States)) {
+		switch manifest.States[name] {
+		case ProcessRunning:
+			commands = append(commands, supervisorctl+" start "+name)
+		case ProcessStopped:
+			commands = append(commands, supervisorctl+" stop "+name)
+		}
+	}
+	return commands, nil
+}
+
+// writeTarFile write one regular file entry into tarball
+// writeTarFile 向 tar 包写入一个普通文件条目
+func writeTarFile(tarWriter *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err := tarWriter.Write(data); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
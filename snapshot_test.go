@@ -0,0 +1,51 @@
+package supervisordkratos_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRestoreHost(t *testing.T) {
+	// Test conf files and process states survive snapshot and restore
+	// 测试 conf 文件和进程状态在快照与恢复后保持一致
+	program := supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/api")
+	group := supervisordkratos.NewGroupConfig("cluster").AddProgram(program)
+
+	sourceDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "cluster.conf"), []byte(supervisordkratos.GenerateGroupConfig(group)), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(sourceDir, "notes.txt"), []byte("not a conf file"), 0o644))
+
+	const status = `cluster:api                      RUNNING   pid 10, uptime 0:05:00
+worker                           STOPPED   Oct 16 09:00 AM
+`
+
+	var buffer bytes.Buffer
+	manifest, err := supervisordkratos.SnapshotHost(&buffer, sourceDir, status, time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, []string{"cluster.conf"}, manifest.Files)
+
+	targetDir := t.TempDir()
+	commands, err := supervisordkratos.RestoreHost(bytes.NewReader(buffer.Bytes()), targetDir, "supervisorctl")
+	require.NoError(t, err)
+	t.Log(commands)
+
+	require.Equal(t, []string{
+		"supervisorctl reread",
+		"supervisorctl update",
+		"supervisorctl start cluster:api",
+		"supervisorctl stop worker",
+	}, commands)
+
+	data, err := os.ReadFile(filepath.Join(targetDir, "cluster.conf"))
+	require.NoError(t, err)
+	require.Equal(t, supervisordkratos.GenerateGroupConfig(group), string(data))
+
+	_, err = supervisordkratos.RestoreHost(bytes.NewReader([]byte("not a tarball")), targetDir, "supervisorctl")
+	require.Error(t, err)
+}
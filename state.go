@@ -0,0 +1,88 @@
+package supervisordkratos
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ProcessState supervisord process state as reported by supervisorctl and events
+// ProcessState supervisorctl 和事件中报告的 supervisord 进程状态
+type ProcessState string
+
+const (
+	ProcessStopped  ProcessState = "STOPPED"  // Stopped or never started // 已停止或从未启动
+	ProcessStarting ProcessState = "STARTING" // Starting due to start request // 正在启动
+	ProcessRunning  ProcessState = "RUNNING"  // Running past startsecs // 运行中（已过 startsecs）
+	ProcessBackoff  ProcessState = "BACKOFF"  // Exited too quickly, waiting to retry // 退出过快，等待重试
+	ProcessStopping ProcessState = "STOPPING" // Stopping due to stop request // 正在停止
+	ProcessExited   ProcessState = "EXITED"   // Exited from RUNNING // 从 RUNNING 状态退出
+	ProcessFatal    ProcessState = "FATAL"    // Could not be started // 无法启动
+	ProcessUnknown  ProcessState = "UNKNOWN"  // Unknown state (supervisord error) // 未知状态（supervisord 错误）
+)
+
+// processTransitions lists states reachable from each state, per supervisord docs
+// BACKOFF goes straight to STOPPED when `stop` arrives while waiting to retry
+//
+// processTransitions 根据 supervisord 文档列出每个状态可达的状态
+// 等待重试时收到 `stop`，BACKOFF 会直接变为 STOPPED
+var processTransitions = map[ProcessState][]ProcessState{
+	ProcessStopped:  {ProcessStarting},
+	ProcessStarting: {ProcessRunning, ProcessBackoff, ProcessStopping},
+	ProcessRunning:  {ProcessStopping, ProcessExited},
+	ProcessBackoff:  {ProcessStarting, ProcessFatal, ProcessStopped},
+	ProcessStopping: {ProcessStopped},
+	ProcessExited:   {ProcessStarting},
+	ProcessFatal:    {ProcessStarting},
+	ProcessUnknown:  {},
+}
+
+// ParseProcessState convert state name text into ProcessState
+// Accepts any letter case and surrounding spaces
+//
+// ParseProcessState 将状态名称文本转换为 ProcessState
+// 接受任意大小写和前后空格
+func ParseProcessState(text string) (ProcessState, error) {
+	state := ProcessState(strings.ToUpper(strings.TrimSpace(text)))
+	if _, ok := processTransitions[state]; !ok {
+		return ProcessUnknown, errors.Errorf("unknown process state %q", text)
+	}
+	return state, nil
+}
+
+// IsRunning checks if process is up and past startsecs
+// IsRunning 检查进程是否已运行并超过 startsecs
+func (s ProcessState) IsRunning() bool {
+	return s == ProcessRunning
+}
+
+// IsFailed checks if process is in a failure state that needs attention
+// IsFailed 检查进程是否处于需要关注的失败状态
+func (s ProcessState) IsFailed() bool {
+	return s == ProcessBackoff || s == ProcessFatal || s == ProcessUnknown
+}
+
+// IsStopped checks if process is down and not going to start by itself
+// IsStopped 检查进程是否已停止且不会自行启动
+func (s ProcessState) IsStopped() bool {
+	return s == ProcessStopped || s == ProcessExited || s == ProcessFatal
+}
+
+// IsTransitional checks if process is moving between states
+// IsTransitional 检查进程是否正在状态之间转换
+func (s ProcessState) IsTransitional() bool {
+	return s == ProcessStarting || s == ProcessStopping || s == ProcessBackoff
+}
+
+// CanTransitionTo checks if supervisord can move process from s to next directly
+// CanTransitionTo 检查 supervisord 能否将进程从 s 直接转换到 next
+func (s ProcessState) CanTransitionTo(next ProcessState) bool {
+	return slices.Contains(processTransitions[s], next)
+}
+
+// NextStates returns states reachable from s directly
+// NextStates 返回从 s 可直接到达的状态
+func (s ProcessState) NextStates() []ProcessState {
+	return slices.Clone(processTransitions[s])
+}
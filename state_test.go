@@ -0,0 +1,38 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProcessState(t *testing.T) {
+	// Test parsing state names from supervisorctl output
+	// 测试从 supervisorctl 输出解析状态名称
+	state, err := supervisordkratos.ParseProcessState(" running ")
+	require.NoError(t, err)
+	require.Equal(t, supervisordkratos.ProcessRunning, state)
+	require.True(t, state.IsRunning())
+	require.False(t, state.IsFailed())
+
+	_, err = supervisordkratos.ParseProcessState("SLEEPING")
+	require.Error(t, err)
+}
+
+func TestProcessStateTransitions(t *testing.T) {
+	// Test transition expectations follow supervisord state diagram
+	// 测试状态转换符合 supervisord 状态图
+	require.True(t, supervisordkratos.ProcessStarting.CanTransitionTo(supervisordkratos.ProcessBackoff))
+	require.True(t, supervisordkratos.ProcessBackoff.CanTransitionTo(supervisordkratos.ProcessFatal))
+	require.True(t, supervisordkratos.ProcessBackoff.CanTransitionTo(supervisordkratos.ProcessStopped))
+	require.False(t, supervisordkratos.ProcessStopped.CanTransitionTo(supervisordkratos.ProcessRunning))
+	require.Equal(t, []supervisordkratos.ProcessState{
+		supervisordkratos.ProcessStopping,
+		supervisordkratos.ProcessExited,
+	}, supervisordkratos.ProcessRunning.NextStates())
+
+	require.True(t, supervisordkratos.ProcessFatal.IsFailed())
+	require.True(t, supervisordkratos.ProcessFatal.IsStopped())
+	require.True(t, supervisordkratos.ProcessStopping.IsTransitional())
+}
@@ -0,0 +1,121 @@
+package supervisordkratos
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// StyleOptions layout choices applied to Document before rendering
+// Zero values keep the default layout
+//
+// StyleOptions 在渲染前应用到 Document 的布局选项
+// 零值保持默认布局
+type StyleOptions struct {
+	EnvWrapWidth  int  // Wrap environment values longer than width onto continuation lines // 将超过宽度的 environment 值换行到续行
+	EnvOnePerLine bool // Put each sorted KEY="VALUE" on its own line, wins over EnvWrapWidth // 每个排序后的 KEY="VALUE" 独占一行，优先于 EnvWrapWidth
+
+	CollapseBlankLines bool // Keep one blank line between sections, e.g. after group header // 段落之间只保留一个空行，例如组段头之后
+}
+
+// NewStyleOptions create new StyleOptions with default layout
+// 创建默认布局的 StyleOptions
+func NewStyleOptions() *StyleOptions {
+	return &StyleOptions{
+		EnvWrapWidth:  0,
+		EnvOnePerLine: false,
+
+		CollapseBlankLines: false,
+	}
+}
+
+// WithEnvWrapWidth set max environment value width per line, 0 disables wrapping
+// 设置每行 environment 值的最大宽度，0 表示不换行
+func (s *StyleOptions) WithEnvWrapWidth(width int) *StyleOptions {
+	s.EnvWrapWidth = width
+	return s
+}
+
+// WithEnvOnePerLine put each environment pair on its own quoted line, easy to eyeball in diffs
+// WithEnvOnePerLine 将每个环境变量键值对放在单独的带引号行上，便于在 diff 中审阅
+func (s *StyleOptions) WithEnvOnePerLine(onePerLine bool) *StyleOptions {
+	s.EnvOnePerLine = onePerLine
+	return s
+}
+
+// WithCollapseBlankLines drop extra blank lines between sections, for ini linters flagging double blanks
+// WithCollapseBlankLines 去除段落之间的额外空行，适用于标记连续空行的 ini 检查工具
+func (s *StyleOptions) WithCollapseBlankLines(collapse bool) *StyleOptions {
+	s.CollapseBlankLines = collapse
+	return s
+}
+
+// Apply rewrite document layout in place and return it for chaining
+// Apply 就地重写文档布局并返回以便链式调用
+func (s *StyleOptions) Apply(document *Document) *Document {
+	for _, section := range document.Sections {
+		if s.CollapseBlankLines {
+			section.Padding = 0
+		}
+		for _, entry := range section.Entries {
+			if entry.Key != "environment" {
+				continue
+			}
+			switch {
+			case s.EnvOnePerLine:
+				entry.Value = wrapPairs(quotePairs(entry.Value), 1)
+			case s.EnvWrapWidth > 0:
+				entry.Value = wrapPairs(entry.Value, s.EnvWrapWidth)
+			}
+		}
+	}
+	return document
+}
+
+// wrapPairs packs comma-separated pairs into lines no longer than width
+// A single pair longer than width gets its own line
+//
+// wrapPairs 将逗号分隔的键值对打包成不超过宽度的行
+// 超过宽度的单个键值对独占一行
+func wrapPairs(value string, width int) string {
+	pairs := splitQuoted(strings.ReplaceAll(value, "\n", ""), ",")
+	lines := make([]string, 0)
+	line := ""
+	for idx, pair := range pairs {
+		if idx < len(pairs)-1 {
+			pair += ","
+		}
+		if line != "" && len(line)+len(pair) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		line += pair
+	}
+	return strings.Join(append(lines, line), "\n")
+}
+
+// quotePairs re-render environment pairs sorted with quoted values
+// supervisord shlex keeps backslashes literal, so values holding '"' are single-quoted instead
+// Values that fail to parse, or hold both quote characters, are returned unchanged
+//
+// quotePairs 将环境变量键值对排序后以带引号的值重新渲染
+// supervisord 的 shlex 将反斜杠视为普通字符，因此包含 '"' 的值改用单引号
+// 无法解析或同时包含两种引号的值原样返回
+func quotePairs(value string) string {
+	items, err := splitSsMap(strings.ReplaceAll(value, "\n", ""), ",")
+	if err != nil {
+		return value
+	}
+	pairs := make([]string, 0, len(items))
+	for _, key := range slices.Sorted(maps.Keys(items)) {
+		quote := `"`
+		if strings.Contains(items[key], `"`) {
+			quote = `'`
+		}
+		if strings.Contains(items[key], quote) {
+			return value
+		}
+		pairs = append(pairs, key+"="+quote+items[key]+quote)
+	}
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,122 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStyleEnvWrapWidth(t *testing.T) {
+	// Test long environment value wraps onto continuation lines
+	// 测试长 environment 值换行到续行
+	program := supervisordkratos.NewProgramConfig(
+		"api",
+		"/opt/api",
+		"deploy",
+		"/var/log/api",
+	).WithEnvironment(map[string]string{
+		"APP_ENV":      "production",
+		"REGION":       "us-east-1",
+		"REGISTRY_URL": "etcd://10.0.0.1:2379",
+		"TRACE_RATIO":  "0.1",
+	})
+
+	document := supervisordkratos.NewStyleOptions().
+		WithEnvWrapWidth(40).
+		Apply(supervisordkratos.NewDocument(supervisordkratos.NewProgramSection(program)))
+	content := document.String()
+	t.Log(content)
+
+	const expected = `[program:api]
+user            = deploy
+directory       = /opt/api
+command         = /opt/api/bin/api
+environment     = APP_ENV=production,REGION=us-east-1,
+    REGISTRY_URL=etcd://10.0.0.1:2379,
+    TRACE_RATIO=0.1
+stdout_logfile  = /var/log/api/api.log
+stderr_logfile  = /var/log/api/api.err
+`
+
+	require.Equal(t, expected, content)
+
+	// Wrapped text parses back into the same environment
+	// 换行后的文本可以解析回相同的环境变量
+	programs, err := supervisordkratos.ParseProgramConfigs(content, supervisordkratos.ParseStrict)
+	require.NoError(t, err)
+	require.Equal(t, program.Environment.Get(), programs[0].Environment.Get())
+}
+
+func TestStyleEnvOnePerLine(t *testing.T) {
+	// Test each environment pair lands on its own quoted line
+	// 测试每个环境变量键值对位于单独的带引号行上
+	program := supervisordkratos.NewProgramConfig(
+		"api",
+		"/opt/api",
+		"deploy",
+		"/var/log/api",
+	).WithEnvironment(map[string]string{
+		"REGION":      "us-east-1",
+		"APP_ENV":     "production",
+		"GREETING":    `say "hi" then go`,
+		"TRACE_RATIO": "0.1",
+		"WIN_PATH":    `C:\app`,
+	})
+
+	document := supervisordkratos.NewStyleOptions().
+		WithEnvWrapWidth(40).
+		WithEnvOnePerLine(true).
+		Apply(supervisordkratos.NewDocument(supervisordkratos.NewProgramSection(program)))
+	content := document.String()
+	t.Log(content)
+
+	const expected = `[program:api]
+user            = deploy
+directory       = /opt/api
+command         = /opt/api/bin/api
+environment     = APP_ENV="production",
+    GREETING='say "hi" then go',
+    REGION="us-east-1",
+    TRACE_RATIO="0.1",
+    WIN_PATH="C:\app"
+stdout_logfile  = /var/log/api/api.log
+stderr_logfile  = /var/log/api/api.err
+`
+
+	require.Equal(t, expected, content)
+
+	// Quoted text parses back into the same environment
+	// 带引号的文本可以解析回相同的环境变量
+	programs, err := supervisordkratos.ParseProgramConfigs(content, supervisordkratos.ParseStrict)
+	require.NoError(t, err)
+	require.Equal(t, program.Environment.Get(), programs[0].Environment.Get())
+}
+
+func TestStyleCollapseBlankLines(t *testing.T) {
+	// Test group header keeps a single blank line when collapsed, default stays unchanged
+	// 测试折叠后组段头只保留一个空行，默认输出保持不变
+	group := supervisordkratos.NewGroupConfig("cluster").
+		AddProgram(supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/api"))
+
+	require.Equal(t, supervisordkratos.GenerateGroupConfig(group), supervisordkratos.NewStyleOptions().Apply(supervisordkratos.NewGroupDocument(group)).String())
+
+	document := supervisordkratos.NewStyleOptions().
+		WithCollapseBlankLines(true).
+		Apply(supervisordkratos.NewGroupDocument(group))
+	content := document.String()
+	t.Log(content)
+
+	const expected = `[group:cluster]
+programs=api
+
+[program:api]
+user            = deploy
+directory       = /opt/api
+command         = /opt/api/bin/api
+stdout_logfile  = /var/log/api/api.log
+stderr_logfile  = /var/log/api/api.err
+`
+
+	require.Equal(t, expected, content)
+}
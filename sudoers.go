@@ -0,0 +1,152 @@
+package supervisordkratos
+
+import (
+	"maps"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/yyle88/must"
+	"github.com/yyle88/printgo"
+)
+
+// supervisorctlActions commands the deploy account may run against each target
+// supervisorctlActions 部署账户可以对每个目标执行的命令
+var supervisorctlActions = []string{"status", "start", "stop", "restart"}
+
+// userNamePattern POSIX portable account name, as accepted by useradd
+// userNamePattern POSIX 可移植账户名称，与 useradd 接受的格式一致
+var userNamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_-]{0,31}$`)
+
+// ErrVisudoNotFound returned when visudo is not on PATH
+// ErrVisudoNotFound 当 PATH 中没有 visudo 时返回
+var ErrVisudoNotFound = errors.New("visudo not found")
+
+// sudoersEscaper escapes characters sudoers treats as syntax inside command arguments
+// sudoersEscaper 转义 sudoers 在命令参数中视为语法的字符
+var sudoersEscaper = strings.NewReplacer(`\`, `\\`, `,`, `\,`, `:`, `\:`, `=`, `\=`)
+
+// sudoersProgram program with the process group it is addressed through and its run-as account
+// sudoersProgram 程序及其访问所用的进程组和运行账户
+type sudoersProgram struct {
+	group    string         // [group:x] name, blank for standalone programs // [group:x] 名称，独立程序为空
+	program  *ProgramConfig // Program config // 程序配置
+	userName string         // Run-as account // 运行账户
+}
+
+// GenerateSudoers generate sudoers snippet letting deployUser supervisorctl-control standalone programs
+// Each instance is listed as explicit target, wildcards would let extra arguments through
+//
+// GenerateSudoers 生成 sudoers 片段，允许 deployUser 通过 supervisorctl 控制独立程序
+// 每个实例都列为明确的目标，通配符会放行额外的参数
+func GenerateSudoers(deployUser string, supervisorctl string, programs ...*ProgramConfig) (string, error) {
+	must.Have(programs)
+	entries := make([]*sudoersProgram, 0, len(programs))
+	for _, program := range programs {
+		entries = append(entries, &sudoersProgram{program: program, userName: program.UserName})
+	}
+	return generateSudoers(deployUser, supervisorctl, entries)
+}
+
+// GenerateGroupSudoers generate sudoers snippet for programs of groups
+// Members are addressed through their [group:x], run-as accounts follow GroupConfig.UserOf
+//
+// GenerateGroupSudoers 为组内程序生成 sudoers 片段
+// 成员通过其 [group:x] 访问，运行账户遵循 GroupConfig.UserOf
+func GenerateGroupSudoers(deployUser string, supervisorctl string, groups ...*GroupConfig) (string, error) {
+	must.Have(groups)
+	entries := make([]*sudoersProgram, 0)
+	for _, group := range groups {
+		for _, program := range group.Programs {
+			entries = append(entries, &sudoersProgram{group: group.Name, program: program, userName: group.UserOf(program)})
+		}
+		for _, program := range group.Standalone {
+			entries = append(entries, &sudoersProgram{program: program, userName: group.UserOf(program)})
+		}
+	}
+	return generateSudoers(deployUser, supervisorctl, entries)
+}
+
+// generateSudoers render sudoers snippet after validating accounts and expanding targets
+// generateSudoers 校验账户并展开目标后渲染 sudoers 片段
+func generateSudoers(deployUser string, supervisorctl string, entries []*sudoersProgram) (string, error) {
+	must.Nice(supervisorctl)
+	// sudo matches commands by full path, a bare name never matches
+	// sudo 按完整路径匹配命令，裸名称永远不会匹配
+	if !filepath.IsAbs(supervisorctl) {
+		return "", errors.Errorf("supervisorctl %q is not an absolute path", supervisorctl)
+	}
+	if !userNamePattern.MatchString(deployUser) {
+		return "", errors.Errorf("invalid deploy user %q", deployUser)
+	}
+
+	runAs := make(map[string][]string)
+	commands := make([]string, 0, len(entries)*len(supervisorctlActions))
+	for _, entry := range entries {
+		program := entry.program
+		if !userNamePattern.MatchString(entry.userName) {
+			return "", errors.Errorf("program %s: invalid user %q", program.Name, entry.userName)
+		}
+		runAs[entry.userName] = append(runAs[entry.userName], program.Name)
+
+		targets, err := supervisorctlTargetsOf(entry)
+		if err != nil {
+			return "", err
+		}
+		for _, target := range targets {
+			for _, action := range supervisorctlActions {
+				commands = append(commands, supervisorctl+" "+action+" "+sudoersEscaper.Replace(target))
+			}
+		}
+	}
+
+	ptx := printgo.NewPTX()
+	ptx.Println("# Managed by supervisordkratos: supervisorctl access for " + deployUser)
+	for _, userName := range slices.Sorted(maps.Keys(runAs)) {
+		ptx.Println("# run as " + userName + ": " + strings.Join(runAs[userName], ", "))
+	}
+	ptx.Println("Cmnd_Alias SUPERVISORDKRATOS_CTL = " + strings.Join(commands, ", \\\n    "))
+	ptx.Println(deployUser + " ALL=(root) NOPASSWD: SUPERVISORDKRATOS_CTL")
+	return ptx.String(), nil
+}
+
+// supervisorctlTargetsOf exact supervisorctl targets of each program instance
+// Single-instance standalone programs keep the bare name, others use group:process form
+//
+// supervisorctlTargetsOf 每个程序实例的精确 supervisorctl 目标
+// 单实例独立程序使用名称本身，其他使用 group:process 形式
+func supervisorctlTargetsOf(entry *sudoersProgram) ([]string, error) {
+	program := entry.program
+	if entry.group == "" && program.NumProcs.Get() == 1 {
+		return []string{program.Name}, nil
+	}
+	processNames, err := program.ProcessNames()
+	if err != nil {
+		return nil, err
+	}
+	group := entry.group
+	if group == "" {
+		group = program.Name
+	}
+	targets := make([]string, 0, len(processNames))
+	for _, processName := range processNames {
+		targets = append(targets, group+":"+processName)
+	}
+	return targets, nil
+}
+
+// CheckSudoers run `visudo -cf` on sudoers file, returns ErrVisudoNotFound when visudo is missing
+// CheckSudoers 对 sudoers 文件运行 `visudo -cf`，没有 visudo 时返回 ErrVisudoNotFound
+func CheckSudoers(path string) error {
+	visudo, err := exec.LookPath("visudo")
+	if err != nil {
+		return ErrVisudoNotFound
+	}
+	if output, err := exec.Command(visudo, "-cf", path).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "visudo: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
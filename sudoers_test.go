@@ -0,0 +1,95 @@
+package supervisordkratos_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSudoers(t *testing.T) {
+	// Test sudoers snippet for programs run as different accounts
+	// 测试以不同账户运行的程序的 sudoers 片段
+	api := supervisordkratos.NewProgramConfig("api", "/opt/api", "svc-api", "/var/log/api").
+		WithNumProcs(2).FixProcessName()
+	worker := supervisordkratos.NewProgramConfig("worker", "/opt/worker", "svc-worker", "/var/log/worker")
+
+	content, err := supervisordkratos.GenerateSudoers("deploy", "/usr/bin/supervisorctl", api, worker)
+	require.NoError(t, err)
+	t.Log(content)
+
+	const expected = `# Managed by supervisordkratos: supervisorctl access for deploy
+# run as svc-api: api
+# run as svc-worker: worker
+Cmnd_Alias SUPERVISORDKRATOS_CTL = /usr/bin/supervisorctl status api\:api_00, \
+    /usr/bin/supervisorctl start api\:api_00, \
+    /usr/bin/supervisorctl stop api\:api_00, \
+    /usr/bin/supervisorctl restart api\:api_00, \
+    /usr/bin/supervisorctl status api\:api_01, \
+    /usr/bin/supervisorctl start api\:api_01, \
+    /usr/bin/supervisorctl stop api\:api_01, \
+    /usr/bin/supervisorctl restart api\:api_01, \
+    /usr/bin/supervisorctl status worker, \
+    /usr/bin/supervisorctl start worker, \
+    /usr/bin/supervisorctl stop worker, \
+    /usr/bin/supervisorctl restart worker
+deploy ALL=(root) NOPASSWD: SUPERVISORDKRATOS_CTL
+`
+
+	require.Equal(t, expected, content)
+	checkSudoers(t, content)
+
+	bad := supervisordkratos.NewProgramConfig("bad", "/opt/bad", "Bad User", "/var/log/bad")
+	_, err = supervisordkratos.GenerateSudoers("deploy", "/usr/bin/supervisorctl", bad)
+	require.Error(t, err)
+
+	_, err = supervisordkratos.GenerateSudoers("deploy", "supervisorctl", worker)
+	require.ErrorContains(t, err, "not an absolute path")
+}
+
+func TestGenerateGroupSudoers(t *testing.T) {
+	// Test group members are addressed through group and run as resolved accounts
+	// 测试组成员通过组访问并以解析后的账户运行
+	group := supervisordkratos.NewGroupConfig("shop").
+		AddProgram(supervisordkratos.NewProgramConfig("api", "/opt/api", "svc-api", "/var/log/api")).
+		AddStandaloneProgram(supervisordkratos.NewProgramConfig("cron", "/opt/cron", "svc-cron", "/var/log/cron")).
+		WithDefaultUser("svc-shop").
+		WithUserOverride("cron", "svc-cron")
+
+	content, err := supervisordkratos.GenerateGroupSudoers("deploy", "/usr/bin/supervisorctl", group)
+	require.NoError(t, err)
+	t.Log(content)
+
+	const expected = `# Managed by supervisordkratos: supervisorctl access for deploy
+# run as svc-cron: cron
+# run as svc-shop: api
+Cmnd_Alias SUPERVISORDKRATOS_CTL = /usr/bin/supervisorctl status shop\:api, \
+    /usr/bin/supervisorctl start shop\:api, \
+    /usr/bin/supervisorctl stop shop\:api, \
+    /usr/bin/supervisorctl restart shop\:api, \
+    /usr/bin/supervisorctl status cron, \
+    /usr/bin/supervisorctl start cron, \
+    /usr/bin/supervisorctl stop cron, \
+    /usr/bin/supervisorctl restart cron
+deploy ALL=(root) NOPASSWD: SUPERVISORDKRATOS_CTL
+`
+
+	require.Equal(t, expected, content)
+	checkSudoers(t, content)
+}
+
+// checkSudoers runs content through visudo when it is installed
+// checkSudoers 在安装了 visudo 时用其检查内容
+func checkSudoers(t *testing.T, content string) {
+	path := filepath.Join(t.TempDir(), "supervisordkratos")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o440))
+	err := supervisordkratos.CheckSudoers(path)
+	if errors.Is(err, supervisordkratos.ErrVisudoNotFound) {
+		t.Log("visudo not installed")
+		return
+	}
+	require.NoError(t, err)
+}
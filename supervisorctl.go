@@ -0,0 +1,157 @@
+package supervisordkratos
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ProcessStatus one process line of `supervisorctl status` output
+// ProcessStatus `supervisorctl status` 输出中的一个进程行
+type ProcessStatus struct {
+	Group       string        // Group name, same as Name for standalone programs // 组名称，独立程序与 Name 相同
+	Name        string        // Process name // 进程名称
+	State       ProcessState  // Process state // 进程状态
+	PID         int           // Process ID when running // 运行时的进程 ID
+	Uptime      time.Duration // Uptime when running // 运行时长
+	Description string        // Raw description column // 原始描述列
+}
+
+// FullName returns group:name form accepted by supervisorctl commands
+// FullName 返回 supervisorctl 命令接受的 group:name 形式
+func (s *ProcessStatus) FullName() string {
+	if s.Group == s.Name {
+		return s.Name
+	}
+	return s.Group + ":" + s.Name
+}
+
+// ParseSupervisorctlStatus parse `supervisorctl status` output into typed results
+// Blank lines are skipped, lines that do not match the status layout cause error
+//
+// ParseSupervisorctlStatus 将 `supervisorctl status` 输出解析为类型化结果
+// 跳过空行，不符合状态格式的行返回错误
+func ParseSupervisorctlStatus(output string) ([]*ProcessStatus, error) {
+	results := make([]*ProcessStatus, 0)
+	for idx, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, errors.Errorf("line %d: missing state in %q", idx+1, line)
+		}
+		state, err := ParseProcessState(fields[1])
+		if err != nil {
+			return nil, errors.WithMessagef(err, "line %d", idx+1)
+		}
+		status := &ProcessStatus{
+			State:       state,
+			Description: strings.Join(fields[2:], " "),
+		}
+		if group, name, ok := strings.Cut(fields[0], ":"); ok {
+			status.Group, status.Name = group, name
+		} else {
+			status.Group, status.Name = fields[0], fields[0]
+		}
+		if state == ProcessRunning {
+			if status.PID, status.Uptime, err = parseRunningDescription(status.Description); err != nil {
+				return nil, errors.WithMessagef(err, "line %d", idx+1)
+			}
+		}
+		results = append(results, status)
+	}
+	return results, nil
+}
+
+// parseRunningDescription parse "pid 1234, uptime 1 day, 2:03:04" description
+// parseRunningDescription 解析 "pid 1234, uptime 1 day, 2:03:04" 描述
+func parseRunningDescription(description string) (int, time.Duration, error) {
+	pidText, uptimeText, ok := strings.Cut(description, ", uptime ")
+	if !ok || !strings.HasPrefix(pidText, "pid ") {
+		return 0, 0, errors.Errorf("invalid running description %q", description)
+	}
+	pid, err := strconv.Atoi(strings.TrimPrefix(pidText, "pid "))
+	if err != nil {
+		return 0, 0, errors.WithStack(err)
+	}
+	var uptime time.Duration
+	if daysText, clockText, ok := strings.Cut(uptimeText, ", "); ok {
+		days, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSuffix(daysText, " days"), " day"))
+		if err != nil {
+			return 0, 0, errors.WithStack(err)
+		}
+		uptime += time.Duration(days) * 24 * time.Hour
+		uptimeText = clockText
+	}
+	parts := strings.Split(uptimeText, ":")
+	if len(parts) != 3 {
+		return 0, 0, errors.Errorf("invalid uptime %q", uptimeText)
+	}
+	for idx, unit := range []time.Duration{time.Hour, time.Minute, time.Second} {
+		value, err := strconv.Atoi(parts[idx])
+		if err != nil {
+			return 0, 0, errors.WithStack(err)
+		}
+		uptime += time.Duration(value) * unit
+	}
+	return pid, uptime, nil
+}
+
+// UpdateResult typed result of `supervisorctl update` output
+// UpdateResult `supervisorctl update` 输出的类型化结果
+type UpdateResult struct {
+	Added   []string          // Groups added // 新增的组
+	Updated []string          // Groups changed and restarted // 变更并重启的组
+	Removed []string          // Groups removed // 删除的组
+	Stopped []string          // Groups stopped before update/removal // 更新或删除前停止的组
+	Errors  map[string]string // Error message per group // 每个组的错误信息
+}
+
+// HasChanges checks if update touched any group
+// HasChanges 检查更新是否涉及任何组
+func (r *UpdateResult) HasChanges() bool {
+	return len(r.Added) > 0 || len(r.Updated) > 0 || len(r.Removed) > 0
+}
+
+// ParseSupervisorctlUpdate parse `supervisorctl update` output into UpdateResult
+// Output with no lines means nothing changed
+//
+// ParseSupervisorctlUpdate 将 `supervisorctl update` 输出解析为 UpdateResult
+// 没有输出行表示没有变化
+func ParseSupervisorctlUpdate(output string) (*UpdateResult, error) {
+	result := &UpdateResult{
+		Added:   make([]string, 0),
+		Updated: make([]string, 0),
+		Removed: make([]string, 0),
+		Stopped: make([]string, 0),
+		Errors:  make(map[string]string),
+	}
+	for idx, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		group, message, ok := strings.Cut(line, ": ")
+		if !ok {
+			return nil, errors.Errorf("line %d: unexpected update output %q", idx+1, line)
+		}
+		switch {
+		case message == "added process group":
+			result.Added = append(result.Added, group)
+		case message == "updated process group":
+			result.Updated = append(result.Updated, group)
+		case message == "removed process group":
+			result.Removed = append(result.Removed, group)
+		case message == "stopped":
+			result.Stopped = append(result.Stopped, group)
+		case strings.HasPrefix(message, "ERROR"):
+			result.Errors[group] = message
+		default:
+			return nil, errors.Errorf("line %d: unexpected update output %q", idx+1, line)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,65 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSupervisorctlStatus(t *testing.T) {
+	// Test parsing typical status output
+	// 测试解析典型的状态输出
+	const output = `api-gateway:api-gateway-00   RUNNING   pid 1234, uptime 0:01:02
+api-gateway:api-gateway-01   RUNNING   pid 1235, uptime 2 days, 3:04:05
+worker                       STOPPED   Oct 16 12:00 AM
+broken                       FATAL     Exited too quickly (process log may have details)
+`
+
+	results, err := supervisordkratos.ParseSupervisorctlStatus(output)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	require.Equal(t, "api-gateway", results[0].Group)
+	require.Equal(t, "api-gateway-00", results[0].Name)
+	require.Equal(t, "api-gateway:api-gateway-00", results[0].FullName())
+	require.Equal(t, 1234, results[0].PID)
+	require.Equal(t, 62*time.Second, results[0].Uptime)
+
+	require.Equal(t, 51*time.Hour+4*time.Minute+5*time.Second, results[1].Uptime)
+
+	require.Equal(t, "worker", results[2].FullName())
+	require.Equal(t, supervisordkratos.ProcessStopped, results[2].State)
+
+	require.True(t, results[3].State.IsFailed())
+	require.Equal(t, "Exited too quickly (process log may have details)", results[3].Description)
+
+	_, err = supervisordkratos.ParseSupervisorctlStatus("worker SLEEPING\n")
+	require.Error(t, err)
+}
+
+func TestParseSupervisorctlUpdate(t *testing.T) {
+	// Test parsing update output into changed groups
+	// 测试将更新输出解析为变更的组
+	const output = `api: stopped
+api: updated process group
+worker: added process group
+legacy: stopped
+legacy: removed process group
+broken: ERROR (spawn error)
+`
+
+	result, err := supervisordkratos.ParseSupervisorctlUpdate(output)
+	require.NoError(t, err)
+	require.True(t, result.HasChanges())
+	require.Equal(t, []string{"worker"}, result.Added)
+	require.Equal(t, []string{"api"}, result.Updated)
+	require.Equal(t, []string{"legacy"}, result.Removed)
+	require.Equal(t, []string{"api", "legacy"}, result.Stopped)
+	require.Equal(t, "ERROR (spawn error)", result.Errors["broken"])
+
+	result, err = supervisordkratos.ParseSupervisorctlUpdate("")
+	require.NoError(t, err)
+	require.False(t, result.HasChanges())
+}
@@ -0,0 +1,186 @@
+package supervisordkratos
+
+import (
+	"maps"
+	"strconv"
+
+	"github.com/yyle88/must"
+	"github.com/yyle88/must/mustslice"
+)
+
+// SupervisordConfig host-wide [supervisord] section settings
+// Rendered once per host, typically into the main supervisord.conf
+//
+// SupervisordConfig 主机级 [supervisord] 段落设置
+// 每台主机渲染一次，通常写入主 supervisord.conf
+type SupervisordConfig struct {
+	// Environment variables // 环境变量
+	Environment *Opt[map[string]string] // Host-wide variables inherited by every program // 所有程序继承的主机级变量
+
+	// Resource limits // 资源限制
+	MinFds   *Opt[int] // Min free file descriptors before starting // 启动前最少空闲文件描述符数
+	MinProcs *Opt[int] // Min free process slots before starting // 启动前最少空闲进程槽数
+
+	// Log settings // 日志设置
+	LogLevel    *Opt[string] // Activity log level // 活动日志级别
+	LogMaxBytes *Opt[string] // Max activity log size // 活动日志最大大小
+	LogBackups  *Opt[int]    // Activity log backup files count // 活动日志备份文件数量
+	ChildLogDir *Opt[string] // DIR of AUTO child logs // AUTO 子进程日志目录
+	NoCleanup   *Opt[bool]   // Keep AUTO child logs at startup // 启动时保留 AUTO 子进程日志
+
+	// Process settings // 进程设置
+	Directory  *Opt[string] // DIR supervisord changes into when daemonizing // 守护化时 supervisord 切换到的目录
+	Identifier *Opt[string] // Identifier exposed over RPC // 通过 RPC 暴露的标识符
+}
+
+// NewSupervisordConfig create new SupervisordConfig with supervisord defaults
+// 创建使用 supervisord 默认值的新 SupervisordConfig
+func NewSupervisordConfig() *SupervisordConfig {
+	return &SupervisordConfig{
+		Environment: NewOpt(make(map[string]string)),
+
+		// Set supervisord standard default values
+		// 设置 supervisord 标准默认值
+		MinFds:      NewOpt(1024),
+		MinProcs:    NewOpt(200),
+		LogLevel:    NewOpt("info"),
+		LogMaxBytes: NewOpt("50MB"),
+		LogBackups:  NewOpt(10),
+		ChildLogDir: NewOpt(""), // supervisord standard default: system temp DIR
+		NoCleanup:   NewOpt(false),
+		Directory:   NewOpt(""), // supervisord standard default: no change
+		Identifier:  NewOpt("supervisor"),
+	}
+}
+
+// WithEnvironment set host-wide environment variables
+// Program environment wins on same names, see EffectiveEnvironment
+//
+// WithEnvironment 设置主机级环境变量
+// 同名时程序环境变量优先，参见 EffectiveEnvironment
+func (c *SupervisordConfig) WithEnvironment(environment map[string]string) *SupervisordConfig {
+	c.Environment.Set(environment)
+	return c
+}
+
+// WithMinFds set min free file descriptors supervisord requires before starting
+// 设置 supervisord 启动前要求的最少空闲文件描述符数
+func (c *SupervisordConfig) WithMinFds(minFds int) *SupervisordConfig {
+	c.MinFds.Set(minFds)
+	return c
+}
+
+// WithMinProcs set min free process slots supervisord requires before starting
+// 设置 supervisord 启动前要求的最少空闲进程槽数
+func (c *SupervisordConfig) WithMinProcs(minProcs int) *SupervisordConfig {
+	c.MinProcs.Set(minProcs)
+	return c
+}
+
+// WithLogLevel set activity log level (critical/error/warn/info/debug/trace/blather)
+// 设置活动日志级别（critical/error/warn/info/debug/trace/blather）
+func (c *SupervisordConfig) WithLogLevel(logLevel string) *SupervisordConfig {
+	mustslice.In(logLevel, []string{"critical", "error", "warn", "info", "debug", "trace", "blather"})
+	c.LogLevel.Set(logLevel)
+	return c
+}
+
+// WithLogMaxBytes set max activity log size
+// 设置活动日志最大大小
+func (c *SupervisordConfig) WithLogMaxBytes(logMaxBytes string) *SupervisordConfig {
+	c.LogMaxBytes.Set(logMaxBytes)
+	return c
+}
+
+// WithLogBackups set activity log backup files count
+// 设置活动日志备份文件数量
+func (c *SupervisordConfig) WithLogBackups(logBackups int) *SupervisordConfig {
+	c.LogBackups.Set(logBackups)
+	return c
+}
+
+// WithChildLogDir set DIR of AUTO child logs
+// 设置 AUTO 子进程日志目录
+func (c *SupervisordConfig) WithChildLogDir(childLogDir string) *SupervisordConfig {
+	c.ChildLogDir.Set(must.Nice(childLogDir))
+	return c
+}
+
+// WithNoCleanup set whether AUTO child logs are kept at startup
+// 设置启动时是否保留 AUTO 子进程日志
+func (c *SupervisordConfig) WithNoCleanup(noCleanup bool) *SupervisordConfig {
+	c.NoCleanup.Set(noCleanup)
+	return c
+}
+
+// WithDirectory set DIR supervisord changes into when daemonizing
+// 设置守护化时 supervisord 切换到的目录
+func (c *SupervisordConfig) WithDirectory(directory string) *SupervisordConfig {
+	c.Directory.Set(must.Nice(directory))
+	return c
+}
+
+// WithIdentifier set identifier exposed over RPC
+// 设置通过 RPC 暴露的标识符
+func (c *SupervisordConfig) WithIdentifier(identifier string) *SupervisordConfig {
+	c.Identifier.Set(must.Nice(identifier))
+	return c
+}
+
+// GenerateSupervisordConfig generate [supervisord] section in INI format
+// GenerateSupervisordConfig 生成 INI 格式的 [supervisord] 段落
+func GenerateSupervisordConfig(config *SupervisordConfig) string {
+	return NewSupervisordSection(config).String()
+}
+
+// NewSupervisordSection build [supervisord] section, only options that differ from defaults
+// NewSupervisordSection 构建 [supervisord] 段落，只包含与默认值不同的选项
+func NewSupervisordSection(config *SupervisordConfig) *Section {
+	section := NewSection("supervisord")
+	if config.Environment.IsSet() {
+		if env := combineSsMap(config.Environment.Get(), ","); env != "" {
+			section.Add("environment", env)
+		}
+	}
+	if config.Directory.IsSet() {
+		section.Add("directory", config.Directory.Get())
+	}
+	if config.Identifier.IsSet() {
+		section.Add("identifier", config.Identifier.Get())
+	}
+	if config.LogLevel.IsSet() {
+		section.Add("loglevel", config.LogLevel.Get())
+	}
+	if config.LogMaxBytes.IsSet() {
+		section.Add("logfile_maxbytes", config.LogMaxBytes.Get())
+	}
+	if config.LogBackups.IsSet() {
+		section.Add("logfile_backups", strconv.Itoa(config.LogBackups.Get()))
+	}
+	if config.ChildLogDir.IsSet() {
+		section.Add("childlogdir", config.ChildLogDir.Get())
+	}
+	if config.NoCleanup.IsSet() {
+		section.Add("nocleanup", strconv.FormatBool(config.NoCleanup.Get()))
+	}
+	if config.MinFds.IsSet() {
+		section.Add("minfds", strconv.Itoa(config.MinFds.Get()))
+	}
+	if config.MinProcs.IsSet() {
+		section.Add("minprocs", strconv.Itoa(config.MinProcs.Get()))
+	}
+	return section
+}
+
+// EffectiveEnvironment merge variables a program process sees from config files
+// supervisord puts [supervisord] environment into its own environment, children inherit it,
+// then [program:x] environment is applied on top, so program values win on same names
+//
+// EffectiveEnvironment 合并程序进程从配置文件中获得的变量
+// supervisord 将 [supervisord] environment 放入自身环境，子进程继承它，
+// 然后在其上应用 [program:x] environment，因此同名时程序的值优先
+func EffectiveEnvironment(config *SupervisordConfig, program *ProgramConfig) map[string]string {
+	results := maps.Clone(config.Environment.Get())
+	maps.Copy(results, program.Environment.Get())
+	return results
+}
@@ -0,0 +1,136 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSupervisordConfig(t *testing.T) {
+	// Test host-wide environment rendered in [supervisord] section
+	// 测试主机级环境变量渲染在 [supervisord] 段落中
+	config := supervisordkratos.NewSupervisordConfig().
+		WithEnvironment(map[string]string{
+			"PATH":        "/opt/tools/bin:/usr/bin:/bin",
+			"HTTPS_PROXY": "http://proxy:3128",
+		})
+
+	content := supervisordkratos.GenerateSupervisordConfig(config)
+	t.Log(content)
+
+	const expected = `[supervisord]
+environment     = HTTPS_PROXY=http://proxy:3128,PATH=/opt/tools/bin:/usr/bin:/bin
+`
+	require.Equal(t, expected, content)
+}
+
+func TestEffectiveEnvironment(t *testing.T) {
+	// Test program environment wins over host-wide environment
+	// 测试程序环境变量优先于主机级环境变量
+	config := supervisordkratos.NewSupervisordConfig().
+		WithEnvironment(map[string]string{"LANG": "C.UTF-8", "LOG_LEVEL": "info"})
+	program := supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/api").
+		WithEnvironment(map[string]string{"LOG_LEVEL": "debug"})
+
+	require.Equal(t, map[string]string{
+		"LANG":      "C.UTF-8",
+		"LOG_LEVEL": "debug",
+	}, supervisordkratos.EffectiveEnvironment(config, program))
+}
+
+func TestSupervisordTuningOptions(t *testing.T) {
+	// Test host-level tuning knobs rendered only when set
+	// 测试主机级调优选项仅在设置时渲染
+	config := supervisordkratos.NewSupervisordConfig().
+		WithMinFds(65536).
+		WithMinProcs(4096).
+		WithLogLevel("warn").
+		WithLogMaxBytes("100MB").
+		WithLogBackups(5).
+		WithChildLogDir("/var/log/supervisor").
+		WithNoCleanup(true).
+		WithDirectory("/srv").
+		WithIdentifier("edge-01")
+
+	content := supervisordkratos.GenerateSupervisordConfig(config)
+	t.Log(content)
+
+	const expected = `[supervisord]
+directory       = /srv
+identifier      = edge-01
+loglevel        = warn
+logfile_maxbytes = 100MB
+logfile_backups = 5
+childlogdir     = /var/log/supervisor
+nocleanup       = true
+minfds          = 65536
+minprocs        = 4096
+`
+	require.Equal(t, expected, content)
+
+	require.Equal(t, "[supervisord]\n", supervisordkratos.GenerateSupervisordConfig(supervisordkratos.NewSupervisordConfig()))
+	require.Panics(t, func() {
+		supervisordkratos.NewSupervisordConfig().WithLogLevel("verbose")
+	})
+}
+
+func TestProgramAutoLogs(t *testing.T) {
+	// Test AUTO logging mode omits log paths and parses back
+	// 测试 AUTO 日志模式省略日志路径并可解析回来
+	config := supervisordkratos.NewSupervisordConfig().WithChildLogDir("/var/log/supervisor")
+	program := supervisordkratos.NewAutoLogsProgramConfig(
+		"api",
+		"/opt/api",
+		"deploy",
+	).WithLogBackups(3)
+
+	content := supervisordkratos.GenerateSupervisordConfig(config) + "\n" + supervisordkratos.GenerateProgramConfig(program)
+	t.Log(content)
+
+	const expected = `[supervisord]
+childlogdir     = /var/log/supervisor
+
+[program:api]
+user            = deploy
+directory       = /opt/api
+command         = /opt/api/bin/api
+stdout_logfile_backups = 3
+stderr_logfile_backups = 3
+`
+	require.Equal(t, expected, content)
+	require.NoError(t, supervisordkratos.ValidateProgramConfig(program))
+	require.NoError(t, supervisordkratos.Simulate(content))
+
+	programs, err := supervisordkratos.ParseProgramConfigs(content, supervisordkratos.ParseStrict)
+	require.NoError(t, err)
+	require.Len(t, programs, 1)
+	require.True(t, programs[0].AutoLogs.Get())
+	require.Empty(t, programs[0].SlogRoot)
+	require.Equal(t, supervisordkratos.GenerateProgramConfig(program), supervisordkratos.GenerateProgramConfig(programs[0]))
+}
+
+func TestCheckEnvExpansions(t *testing.T) {
+	// Test %(ENV_NAME)s roots must be declared and expand to absolute paths
+	// 测试 %(ENV_NAME)s 根路径必须已声明且展开后为绝对路径
+	program := supervisordkratos.NewProgramConfig(
+		"api",
+		"/opt/api",
+		"deploy",
+		"%(ENV_LOG_ROOT)s/api",
+	)
+	content := supervisordkratos.GenerateProgramConfig(program)
+	t.Log(content)
+	require.Contains(t, content, "stdout_logfile  = %(ENV_LOG_ROOT)s/api/api.log\n")
+	require.NoError(t, supervisordkratos.ValidateProgramConfig(program))
+	require.NoError(t, supervisordkratos.Simulate(content))
+
+	config := supervisordkratos.NewSupervisordConfig()
+	require.Error(t, config.CheckEnvExpansions(program))
+
+	config.WithEnvironment(map[string]string{"LOG_ROOT": "var/log"})
+	require.Error(t, config.CheckEnvExpansions(program))
+
+	config.WithEnvironment(map[string]string{"LOG_ROOT": "/data/log"})
+	require.NoError(t, config.CheckEnvExpansions(program))
+}
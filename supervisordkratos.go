@@ -8,16 +8,25 @@
 package supervisordkratos
 
 import (
-	"path/filepath"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/yyle88/must"
 	"github.com/yyle88/must/mustslice"
-	"github.com/yyle88/printgo"
 )
 
+// DisabledMarker comment placed above [program:x] header of disabled programs
+// DisabledMarker 放在停放程序 [program:x] 段头上方的注释
+const DisabledMarker = "DISABLED: parked by supervisordkratos, autostart forced to false"
+
+// AutoLogfile supervisord log path value meaning a generated file in childlogdir
+// AutoLogfile supervisord 日志路径值，表示在 childlogdir 中自动生成的文件
+const AutoLogfile = "AUTO"
+
 // ProgramConfig single program configuration
 // 单个程序配置
 type ProgramConfig struct {
@@ -27,6 +36,8 @@ type ProgramConfig struct {
 	Root     string // Program root DIR // 程序根目录
 	SlogRoot string // Standard output log root DIR // 标准输出日志根目录
 
+	BinaryName string // Binary file under Root/bin, blank means Name // Root/bin 下的二进制文件名，为空表示使用 Name
+
 	// Environment variables // 环境变量
 	Environment *Opt[map[string]string] // Environment variables // 环境变量
 
@@ -40,6 +51,8 @@ type ProgramConfig struct {
 	LogMaxBytes    *Opt[string] // Max log file size // 最大日志文件大小
 	LogBackups     *Opt[int]    // Log backup files count // 日志备份文件数量
 	RedirectStderr *Opt[bool]   // Redirect stderr to stdout // 重定向 stderr 到 stdout
+	AutoLogs       *Opt[bool]   // Omit log paths, supervisord writes AUTO logs into childlogdir // 省略日志路径，supervisord 将 AUTO 日志写入 childlogdir
+	InstanceLogs   *Opt[bool]   // One log file per instance through %(process_num) // 通过 %(process_num) 为每个实例使用单独的日志文件
 
 	// Advanced process settings // 高级进程设置
 	StopAsGroup  *Opt[bool]   // Stop processes as group // 作为组停止进程
@@ -52,6 +65,38 @@ type ProgramConfig struct {
 	// Multi-instance settings // 多实例设置
 	NumProcs    *Opt[int]    // Process instance count // 进程实例数量
 	ProcessName *Opt[string] // Process name template // 进程名称模板
+
+	// Parking settings // 停放设置
+	Disabled *Opt[bool] // Parked: rendered with autostart=false and marker comment // 停放：以 autostart=false 和标记注释渲染
+
+	// Selection labels, not rendered // 选择标签，不渲染
+	Labels map[string]string // Labels like team=payments, tier=edge // 标签，例如 team=payments、tier=edge
+
+	// Start order declarations, not rendered // 启动顺序声明，不渲染
+	DependsOn []string // Programs that must start first, checked against Priority // 必须先启动的程序，根据 Priority 检查
+
+	// Annotations, rendered as comments // 注解，以注释形式渲染
+	Resources     *Opt[*Resources] // Expected CPU/memory budget // 预期的 CPU/内存预算
+	BinaryVersion *Opt[string]     // Version of the built binary, blank when unknown // 已构建二进制的版本，未知时为空
+
+	// Target settings // 目标设置
+	TargetOS *Opt[TargetOS] // OS the config runs on, decides path separators // 配置运行的操作系统，决定路径分隔符
+
+	// Command wrapping settings // 命令包装设置
+	Sandbox     *Opt[*Sandbox]      // Isolation wrapping the command // 包装命令的隔离设置
+	Maintenance *Opt[string]        // Placeholder command replacing the binary, blank when off // 替换二进制的占位命令，空表示关闭
+	Schedule    *Opt[time.Duration] // Run command in a sleep loop with this interval // 以该间隔在睡眠循环中运行命令
+	CPUAffinity *Opt[string]        // CPU list for taskset -c, blank when not pinned // taskset -c 的 CPU 列表，空表示不绑定
+	Readiness   *Opt[*Readiness]    // Probe creating marker file, runs the binary via wrapper script // 创建标记文件的探针，通过包装脚本运行二进制
+	PreStopHook *Opt[*PreStopHook]  // Command run before stop signal, runs the binary via wrapper script // 停止信号之前运行的命令，通过包装脚本运行二进制
+
+	// Operator settings // 运维设置
+	Alias     *Opt[string]    // Short group name wrapping this program alone // 只包含该程序的短组名
+	Archetype *Opt[Archetype] // Archetype whose curated options were applied // 已应用预设选项的形态
+
+	// Raw options // 原始选项
+	RawOptions []*Entry            // Unknown options kept by lenient parsing, emitted as-is // 宽松解析保留的未知选项，原样输出
+	Comments   map[string][]string // Comment lines by option name, "" for section header // 按选项名称保存的注释行，"" 表示段头
 }
 
 // NewProgramConfig create new ProgramConfig with required fields
@@ -60,12 +105,29 @@ type ProgramConfig struct {
 // 创建新的 ProgramConfig，需要提供必填字段
 // Name、Root、UserName、SlogRoot 是必填参数
 func NewProgramConfig(name string, root string, userName string, slogRoot string) *ProgramConfig {
+	return newProgramConfig(name, root, userName, must.Nice(slogRoot))
+}
+
+// NewAutoLogsProgramConfig create ProgramConfig writing supervisord AUTO logs, no slog root needed
+// Logs land in [supervisord] childlogdir, see WithAutoLogs
+//
+// NewAutoLogsProgramConfig 创建写入 supervisord AUTO 日志的 ProgramConfig，无需日志根目录
+// 日志写入 [supervisord] childlogdir，参见 WithAutoLogs
+func NewAutoLogsProgramConfig(name string, root string, userName string) *ProgramConfig {
+	return newProgramConfig(name, root, userName, "").WithAutoLogs(true)
+}
+
+// newProgramConfig create ProgramConfig with defaults, slogRoot may be blank in AUTO logs mode
+// newProgramConfig 创建带有默认值的 ProgramConfig，AUTO 日志模式下 slogRoot 可以为空
+func newProgramConfig(name string, root string, userName string, slogRoot string) *ProgramConfig {
 	return &ProgramConfig{
 		// Basic program information // 基本程序信息
 		Name:     must.Nice(name),
 		UserName: must.Nice(userName),
 		Root:     must.Nice(root),
-		SlogRoot: must.Nice(slogRoot),
+		SlogRoot: slogRoot,
+
+		BinaryName: "",
 
 		// Environment variables // 环境变量
 		Environment: NewOpt(make(map[string]string)),
@@ -83,6 +145,8 @@ func NewProgramConfig(name string, root string, userName string, slogRoot string
 		LogMaxBytes:    NewOpt("50MB"),
 		LogBackups:     NewOpt(10),
 		RedirectStderr: NewOpt(false),
+		AutoLogs:       NewOpt(false),
+		InstanceLogs:   NewOpt(false),
 
 		// Advanced process settings defaults
 		// 高级进程设置默认值
@@ -97,6 +161,40 @@ func NewProgramConfig(name string, root string, userName string, slogRoot string
 		// 多实例默认值
 		NumProcs:    NewOpt(1),
 		ProcessName: NewOpt("%(program_name)s"),
+
+		// Parking defaults
+		// 停放默认值
+		Disabled: NewOpt(false),
+
+		// Selection labels // 选择标签
+		Labels: make(map[string]string),
+
+		// Start order declarations // 启动顺序声明
+		DependsOn: make([]string, 0),
+
+		// Annotations // 注解
+		Resources:     NewOpt[*Resources](nil),
+		BinaryVersion: NewOpt(""),
+
+		// Target defaults, Linux hosts use forward slashes
+		// 目标默认值，Linux 主机使用正斜杠
+		TargetOS: NewOpt(TargetLinux),
+
+		// Command wrapping defaults // 命令包装默认值
+		Sandbox:     NewOpt[*Sandbox](nil),
+		Maintenance: NewOpt(""),
+		Schedule:    NewOpt(time.Duration(0)),
+		CPUAffinity: NewOpt(""),
+		Readiness:   NewOpt[*Readiness](nil),
+		PreStopHook: NewOpt[*PreStopHook](nil),
+
+		// Operator defaults // 运维默认值
+		Alias:     NewOpt(""),
+		Archetype: NewOpt(Archetype("")),
+
+		// Raw options // 原始选项
+		RawOptions: make([]*Entry, 0),
+		Comments:   make(map[string][]string),
 	}
 }
 
@@ -155,6 +253,33 @@ func (p *ProgramConfig) WithLogBackups(logBackups int) *ProgramConfig {
 	return p
 }
 
+// WithAutoLogs set whether log paths are omitted in favor of supervisord AUTO logs
+// SlogRoot is ignored then, logs land in [supervisord] childlogdir, see SupervisordConfig.WithChildLogDir
+//
+// WithAutoLogs 设置是否省略日志路径，改用 supervisord AUTO 日志
+// 此时忽略 SlogRoot，日志写入 [supervisord] childlogdir，参见 SupervisordConfig.WithChildLogDir
+func (p *ProgramConfig) WithAutoLogs(autoLogs bool) *ProgramConfig {
+	p.AutoLogs.Set(autoLogs)
+	return p
+}
+
+// WithBinaryName set binary file name under Root/bin when it differs from program name
+// WithBinaryName 当二进制文件名与程序名称不同时设置 Root/bin 下的文件名
+func (p *ProgramConfig) WithBinaryName(binaryName string) *ProgramConfig {
+	p.BinaryName = must.Nice(binaryName)
+	return p
+}
+
+// WithInstanceLogs set whether log file names carry process_num, numprocs > 1 needs it
+// Otherwise every instance appends to the same file and rotation races between them
+//
+// WithInstanceLogs 设置日志文件名是否带有 process_num，numprocs > 1 时需要
+// 否则所有实例追加写入同一个文件，轮转时相互竞争
+func (p *ProgramConfig) WithInstanceLogs(instanceLogs bool) *ProgramConfig {
+	p.InstanceLogs.Set(instanceLogs)
+	return p
+}
+
 // WithRedirectStderr set stderr redirect flag
 // 设置标准错误重定向标志
 func (p *ProgramConfig) WithRedirectStderr(redirectStderr bool) *ProgramConfig {
@@ -225,6 +350,55 @@ func (p *ProgramConfig) WithProcessName(processName string) *ProgramConfig {
 	return p
 }
 
+// WithDisabled park program without deleting its config
+// Disabled program renders autostart=false with DisabledMarker comment
+//
+// WithDisabled 停放程序而不删除其配置
+// 停放的程序渲染为 autostart=false 并带有 DisabledMarker 注释
+func (p *ProgramConfig) WithDisabled(disabled bool) *ProgramConfig {
+	p.Disabled.Set(disabled)
+	return p
+}
+
+// WithTargetOS set OS the config runs on, paths use its separators
+// 设置配置运行的操作系统，路径使用其分隔符
+func (p *ProgramConfig) WithTargetOS(targetOS TargetOS) *ProgramConfig {
+	mustslice.In(targetOS, []TargetOS{TargetLinux, TargetWindows})
+	p.TargetOS.Set(targetOS)
+	return p
+}
+
+// WithBaseDir resolve relative Root and SlogRoot against absolute baseDir
+// Absolute and %(ENV_NAME)s-rooted paths are kept as they are
+//
+// WithBaseDir 将相对的 Root 和 SlogRoot 基于绝对路径 baseDir 解析
+// 绝对路径和以 %(ENV_NAME)s 开头的路径保持不变
+func (p *ProgramConfig) WithBaseDir(baseDir string) *ProgramConfig {
+	targetOS := p.TargetOS.Get()
+	must.True(targetOS.IsAbs(baseDir))
+	if !isEnvRooted(p.Root) && !targetOS.IsAbs(p.Root) {
+		p.Root = targetOS.Join(baseDir, p.Root)
+	}
+	if !p.AutoLogs.Get() && !isEnvRooted(p.SlogRoot) && !targetOS.IsAbs(p.SlogRoot) {
+		p.SlogRoot = targetOS.Join(baseDir, p.SlogRoot)
+	}
+	return p
+}
+
+// WithLabel set selection label, used by label selectors
+// 设置选择标签，供标签选择器使用
+func (p *ProgramConfig) WithLabel(key string, value string) *ProgramConfig {
+	p.Labels[must.Nice(key)] = value
+	return p
+}
+
+// WithComment add comment lines above option key, use "" for section header
+// 在选项上方添加注释行，"" 表示段头
+func (p *ProgramConfig) WithComment(key string, comments ...string) *ProgramConfig {
+	p.Comments[key] = append(p.Comments[key], comments...)
+	return p
+}
+
 // GenerateProgramConfig generate single program configuration from ProgramConfig
 // Creates supervisord INI format config with explicit values (no spacing inside)
 // Includes basic info, process settings, log paths, and advanced settings
@@ -235,96 +409,206 @@ func (p *ProgramConfig) WithProcessName(processName string) *ProgramConfig {
 // 包括基础信息、进程控制、日志路径和高级设置
 // 省略默认值以保持配置简洁，专注于用户设置
 func GenerateProgramConfig(program *ProgramConfig) string {
+	// Aliased programs come with their one-member group
+	// 带别名的程序附带只有自身的组
+	if program.Alias.IsSet() {
+		return NewAliasDocument(program).String()
+	}
+	return NewProgramSection(program).String()
+}
+
+// NewProgramSection build [program:x] Section from ProgramConfig
+// Entries follow the same rules as GenerateProgramConfig, ready to post-process
+//
+// NewProgramSection 从 ProgramConfig 构建 [program:x] 段落
+// 条目规则与 GenerateProgramConfig 相同，可进行后续处理
+func NewProgramSection(program *ProgramConfig) *Section {
 	must.Full(program)
 	must.Nice(program.Name)
 	must.Nice(program.Root)
 	must.Nice(program.UserName)
-	must.Nice(program.SlogRoot)
-
-	ptx := printgo.NewPTX()
+	must.True(program.AutoLogs.Get() || program.SlogRoot != "")
 
 	// Generate program section and basic required settings
 	// 生成程序段落和基本必需设置
-	ptx.Println("[program:" + program.Name + "]")
-	ptx.Println("user            = " + program.UserName)
-	ptx.Println("directory       = " + program.Root)
-	ptx.Println("command         = " + filepath.Join(program.Root, "bin", program.Name))
+	section := NewSection("program:" + program.Name)
+	section.Add("user", program.UserName)
+	section.Add("directory", program.TargetOS.Get().Clean(program.Root))
+	section.Add("command", program.commandLine())
+	if program.Maintenance.Get() != "" {
+		section.Comments = append(section.Comments, MaintenanceMarker)
+	}
+	if program.Resources.IsSet() {
+		section.Comments = append(section.Comments, program.Resources.Get().Comment())
+	}
+	if program.BinaryVersion.IsSet() {
+		section.Comments = append(section.Comments, "binary: version="+program.BinaryVersion.Get())
+	}
 	// Add environment variables if set
 	// 添加环境变量（如果已设置）
 	if program.Environment.IsSet() {
 		if env := combineSsMap(program.Environment.Get(), ","); env != "" {
-			ptx.Println("environment     = " + env)
+			section.Add("environment", env)
 		}
 	}
 	// Process settings - just print explicit values
 	// 进程设置 - 只打印显式设置的值
-	if program.AutoStart.IsSet() {
-		ptx.Println("autostart       = " + strconv.FormatBool(program.AutoStart.Get()))
+	if program.Disabled.Get() {
+		// Parked program never starts with supervisord
+		// 停放的程序不随 supervisord 启动
+		section.Comments = append(section.Comments, DisabledMarker)
+		section.Add("autostart", "false")
+	} else if program.AutoStart.IsSet() {
+		section.Add("autostart", strconv.FormatBool(program.AutoStart.Get()))
 	}
 	if program.AutoRestart.IsSet() {
-		value := program.AutoRestart.Get()
-		switch v := value.(type) {
-		case bool:
-			ptx.Println("autorestart     = " + strconv.FormatBool(v))
-		case string:
-			ptx.Println("autorestart     = " + v)
-		default:
-			panic(errors.New("IMPOSSIBLE: INVALID TYPE"))
-		}
+		section.Add("autorestart", formatAutoRestart(program.AutoRestart.Get()))
 	}
 	if program.StartRetries.IsSet() {
-		ptx.Println("startretries    = " + strconv.Itoa(program.StartRetries.Get()))
+		section.Add("startretries", strconv.Itoa(program.StartRetries.Get()))
 	}
 	if program.StartSecs.IsSet() {
-		ptx.Println("startsecs       = " + strconv.Itoa(program.StartSecs.Get()))
+		section.Add("startsecs", strconv.Itoa(program.StartSecs.Get()))
+	}
+	// Log paths show unless AUTO logs leave them to supervisord, rotation only when set
+	// 日志路径始终显示，除非 AUTO 日志交给 supervisord 处理；轮转设置仅在设置时显示
+	if !program.AutoLogs.Get() {
+		section.Add("stdout_logfile", program.stdoutLogfile())
 	}
-	// Log settings always show (required for paths)
-	// 日志设置始终显示（路径必需）
-	ptx.Println("stdout_logfile  = " + filepath.Join(program.SlogRoot, program.Name+".log"))
 	if program.LogMaxBytes.IsSet() {
-		ptx.Println("stdout_logfile_maxbytes = " + program.LogMaxBytes.Get())
+		section.Add("stdout_logfile_maxbytes", program.LogMaxBytes.Get())
 	}
 	if program.LogBackups.IsSet() {
-		ptx.Println("stdout_logfile_backups = " + strconv.Itoa(program.LogBackups.Get()))
+		section.Add("stdout_logfile_backups", strconv.Itoa(program.LogBackups.Get()))
+	}
+	if !program.AutoLogs.Get() {
+		section.Add("stderr_logfile", program.stderrLogfile())
 	}
-	ptx.Println("stderr_logfile  = " + filepath.Join(program.SlogRoot, program.Name+".err"))
 	if program.LogMaxBytes.IsSet() {
-		ptx.Println("stderr_logfile_maxbytes = " + program.LogMaxBytes.Get())
+		section.Add("stderr_logfile_maxbytes", program.LogMaxBytes.Get())
 	}
 	if program.LogBackups.IsSet() {
-		ptx.Println("stderr_logfile_backups = " + strconv.Itoa(program.LogBackups.Get()))
+		section.Add("stderr_logfile_backups", strconv.Itoa(program.LogBackups.Get()))
 	}
 	if program.RedirectStderr.IsSet() {
-		ptx.Println("redirect_stderr = " + strconv.FormatBool(program.RedirectStderr.Get()))
+		section.Add("redirect_stderr", strconv.FormatBool(program.RedirectStderr.Get()))
 	}
 	// Advanced process settings - just non-defaults
 	// 高级进程设置 - 只显示非默认值
 	if program.StopAsGroup.IsSet() {
-		ptx.Println("stopasgroup     = " + strconv.FormatBool(program.StopAsGroup.Get()))
+		section.Add("stopasgroup", strconv.FormatBool(program.StopAsGroup.Get()))
 	}
 	if program.StopWaitSecs.IsSet() {
-		ptx.Println("stopwaitsecs    = " + strconv.Itoa(program.StopWaitSecs.Get()))
+		section.Add("stopwaitsecs", strconv.Itoa(program.StopWaitSecs.Get()))
 	}
 	if program.KillAsGroup.IsSet() {
-		ptx.Println("killasgroup     = " + strconv.FormatBool(program.KillAsGroup.Get()))
+		section.Add("killasgroup", strconv.FormatBool(program.KillAsGroup.Get()))
 	}
 	if program.StopSignal.IsSet() {
-		ptx.Println("stopsignal      = " + program.StopSignal.Get())
+		section.Add("stopsignal", program.StopSignal.Get())
 	}
 	if program.Priority.IsSet() {
-		ptx.Println("priority        = " + strconv.Itoa(program.Priority.Get()))
+		section.Add("priority", strconv.Itoa(program.Priority.Get()))
 	}
 	if program.ExitCodes.IsSet() {
-		ptx.Println("exitcodes       = " + combineInts(program.ExitCodes.Get(), ","))
+		section.Add("exitcodes", combineInts(program.ExitCodes.Get(), ","))
 	}
 	if program.NumProcs.IsSet() {
-		ptx.Println("numprocs        = " + strconv.Itoa(program.NumProcs.Get()))
+		section.Add("numprocs", strconv.Itoa(program.NumProcs.Get()))
 	}
 	if program.ProcessName.IsSet() {
-		ptx.Println("process_name    = " + program.ProcessName.Get())
+		section.Add("process_name", program.ProcessName.Get())
+	}
+	// Raw options come last, exactly as parsed
+	// 原始选项放在最后，与解析时完全一致
+	for _, entry := range program.RawOptions {
+		section.Add(entry.Key, entry.Value)
 	}
+	// Attach comments to header and emitted options
+	// 将注释附加到段头和已输出的选项
+	section.Comments = append(section.Comments, program.Comments[""]...)
+	for _, entry := range section.Entries {
+		entry.Comments = append(entry.Comments, program.Comments[entry.Key]...)
+	}
+
+	return section
+}
+
+// commandPath returns the binary path resolved as Root/bin/Name, or Root/bin/BinaryName when set
+// commandPath 返回解析为 Root/bin/Name 的二进制路径，设置 BinaryName 时为 Root/bin/BinaryName
+func (p *ProgramConfig) commandPath() string {
+	binaryName := p.Name
+	if p.BinaryName != "" {
+		binaryName = p.BinaryName
+	}
+	return p.TargetOS.Get().Join(p.Root, "bin", binaryName)
+}
+
+// commandLine returns full command with wrappers applied around commandPath
+// commandLine 返回在 commandPath 外应用包装后的完整命令
+func (p *ProgramConfig) commandLine() string {
+	// Maintenance placeholder replaces the whole command
+	// 维护占位命令替换整个命令
+	if placeholder := p.Maintenance.Get(); placeholder != "" {
+		return placeholder
+	}
+	command := p.sandboxedCommand()
+	// Wrapper script runs the sandboxed binary as its child
+	// 包装脚本以子进程方式运行沙箱中的二进制
+	if p.needsWrapper() {
+		command = WrapperScriptPath(p)
+	}
+	// Scheduled jobs run each round in the sandbox, the loop stays outside
+	// 定时任务的每一轮在沙箱中运行，循环位于沙箱之外
+	if p.Schedule.IsSet() {
+		command = scheduleLoop(command, p.Schedule.Get())
+	}
+	// Affinity is inherited by children, so taskset goes outermost
+	// 亲和性会被子进程继承，因此 taskset 位于最外层
+	if cpus := p.CPUAffinity.Get(); cpus != "" {
+		command = "taskset -c " + cpus + " " + command
+	}
+	return command
+}
+
+// stdoutLogfile returns the stdout log path resolved from SlogRoot and Name, AUTO in AutoLogs mode
+// stdoutLogfile 返回由 SlogRoot 和 Name 解析出的标准输出日志路径，AutoLogs 模式下为 AUTO
+func (p *ProgramConfig) stdoutLogfile() string {
+	if p.AutoLogs.Get() {
+		return AutoLogfile
+	}
+	return p.TargetOS.Get().Join(p.SlogRoot, p.logName()+".log")
+}
 
-	return ptx.String()
+// stderrLogfile returns the stderr log path resolved from SlogRoot and Name, AUTO in AutoLogs mode
+// stderrLogfile 返回由 SlogRoot 和 Name 解析出的标准错误日志路径，AutoLogs 模式下为 AUTO
+func (p *ProgramConfig) stderrLogfile() string {
+	if p.AutoLogs.Get() {
+		return AutoLogfile
+	}
+	return p.TargetOS.Get().Join(p.SlogRoot, p.logName()+".err")
+}
+
+// logName log file name without extension, with process_num suffix when InstanceLogs is set
+// logName 不含扩展名的日志文件名，设置 InstanceLogs 时带有 process_num 后缀
+func (p *ProgramConfig) logName() string {
+	if p.InstanceLogs.Get() {
+		return p.Name + processNumSuffix
+	}
+	return p.Name
+}
+
+// formatAutoRestart converts bool or "unexpected" mode to autorestart value
+// formatAutoRestart 将布尔值或 "unexpected" 模式转换为 autorestart 值
+func formatAutoRestart(value any) string {
+	switch v := value.(type) {
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return v
+	default:
+		panic(errors.New("IMPOSSIBLE: INVALID TYPE"))
+	}
 }
 
 // combineInts converts int slice to comma-separated string
@@ -345,18 +629,20 @@ func combineInts(items []int, sep string) string {
 
 // combineSsMap converts string map to name=value pairs joined with sep
 // Used to format environment variables as KEY1=VALUE1,KEY2=VALUE2
+// Pairs are sorted by name so output is stable across runs
 // Returns blank string if input is blank
 //
 // combineSsMap 将字符串映射转换为由分隔符连接的键值对
 // 用于格式化环境变量为 KEY1=VALUE1,KEY2=VALUE2 格式
+// 键值对按名称排序，使输出在多次运行间保持稳定
 // 输入为空时返回空字符串
 func combineSsMap(items map[string]string, sep string) string {
 	if len(items) == 0 {
 		return ""
 	}
 	pairs := make([]string, 0, len(items))
-	for key, value := range items {
-		pairs = append(pairs, key+"="+value)
+	for _, key := range slices.Sorted(maps.Keys(items)) {
+		pairs = append(pairs, key+"="+items[key])
 	}
 	return strings.Join(pairs, sep)
 }
@@ -0,0 +1,97 @@
+package supervisordkratos
+
+import (
+	"path"
+	"strings"
+)
+
+// TargetOS operating system the generated config runs on, decides path separators
+// Paths never depend on the OS that runs the generator
+//
+// TargetOS 生成的配置所运行的操作系统，决定路径分隔符
+// 路径不依赖于运行生成器的操作系统
+type TargetOS string
+
+const (
+	TargetLinux   TargetOS = "linux"   // Forward slashes, e.g. /opt/app // 正斜杠，例如 /opt/app
+	TargetWindows TargetOS = "windows" // Backslashes, e.g. C:\opt\app // 反斜杠，例如 C:\opt\app
+)
+
+// Join joins path elements with target separator and cleans the result
+// Join 使用目标分隔符连接路径元素并清理结果
+func (t TargetOS) Join(elem ...string) string {
+	slashed := make([]string, 0, len(elem))
+	for _, item := range elem {
+		slashed = append(slashed, t.toSlash(item))
+	}
+	return t.fromSlash(t.cleanSlashed(path.Join(slashed...), len(slashed) > 0 && t.isUNC(slashed[0])))
+}
+
+// Clean removes dot segments and trailing separators
+// Clean 移除点段和尾部分隔符
+func (t TargetOS) Clean(p string) string {
+	slashed := t.toSlash(p)
+	return t.fromSlash(t.cleanSlashed(path.Clean(slashed), t.isUNC(slashed)))
+}
+
+// Dir returns all but the last element of path
+// Dir 返回路径中除最后一个元素之外的部分
+func (t TargetOS) Dir(p string) string {
+	slashed := t.toSlash(p)
+	return t.fromSlash(t.cleanSlashed(path.Dir(slashed), t.isUNC(slashed)))
+}
+
+// IsAbs checks if path is absolute on target OS
+// Windows accepts drive paths like C:\x and UNC paths like \\host\share
+//
+// IsAbs 检查路径在目标操作系统上是否为绝对路径
+// Windows 接受 C:\x 形式的盘符路径和 \\host\share 形式的 UNC 路径
+func (t TargetOS) IsAbs(p string) bool {
+	if t != TargetWindows {
+		return strings.HasPrefix(p, "/")
+	}
+	slashed := t.toSlash(p)
+	if t.isUNC(slashed) {
+		return true
+	}
+	return len(slashed) >= 3 && slashed[1] == ':' && slashed[2] == '/' && isAsciiLetter(slashed[0])
+}
+
+// toSlash converts target separators to forward slashes
+// toSlash 将目标分隔符转换为正斜杠
+func (t TargetOS) toSlash(p string) string {
+	if t == TargetWindows {
+		return strings.ReplaceAll(p, `\`, "/")
+	}
+	return p
+}
+
+// fromSlash converts forward slashes to target separators
+// fromSlash 将正斜杠转换为目标分隔符
+func (t TargetOS) fromSlash(p string) string {
+	if t == TargetWindows {
+		return strings.ReplaceAll(p, "/", `\`)
+	}
+	return p
+}
+
+// isUNC checks if slashed path is a Windows UNC path like //host/share
+// isUNC 检查正斜杠形式的路径是否为 //host/share 形式的 Windows UNC 路径
+func (t TargetOS) isUNC(slashed string) bool {
+	return t == TargetWindows && strings.HasPrefix(slashed, "//")
+}
+
+// cleanSlashed restores UNC double slash prefix that path functions collapse
+// cleanSlashed 恢复被 path 函数合并的 UNC 双斜杠前缀
+func (t TargetOS) cleanSlashed(p string, unc bool) string {
+	if unc && !strings.HasPrefix(p, "//") {
+		return "/" + p
+	}
+	return p
+}
+
+// isAsciiLetter checks if c is an ASCII letter
+// isAsciiLetter 检查 c 是否为 ASCII 字母
+func isAsciiLetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
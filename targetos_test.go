@@ -0,0 +1,48 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetOSPaths(t *testing.T) {
+	// Test path helpers per target OS
+	// 测试各目标操作系统的路径函数
+	require.Equal(t, "/opt/api/bin/api", supervisordkratos.TargetLinux.Join("/opt/api/", "bin", "api"))
+	require.True(t, supervisordkratos.TargetLinux.IsAbs("/opt/api"))
+	require.False(t, supervisordkratos.TargetLinux.IsAbs(`C:\opt\api`))
+
+	require.Equal(t, `C:\opt\api\bin\api.exe`, supervisordkratos.TargetWindows.Join(`C:\opt\api\`, "bin", "api.exe"))
+	require.Equal(t, `\\host\share\api`, supervisordkratos.TargetWindows.Clean(`\\host\share\x\..\api`))
+	require.Equal(t, `C:\logs`, supervisordkratos.TargetWindows.Dir(`C:\logs\api.log`))
+	require.True(t, supervisordkratos.TargetWindows.IsAbs(`C:\opt\api`))
+	require.True(t, supervisordkratos.TargetWindows.IsAbs(`\\host\share`))
+	require.False(t, supervisordkratos.TargetWindows.IsAbs(`opt\api`))
+}
+
+func TestWindowsTargetProgram(t *testing.T) {
+	// Test program generated for supervisord-win hosts uses backslashes
+	// 测试为 supervisord-win 主机生成的程序使用反斜杠
+	program := supervisordkratos.NewProgramConfig(
+		"api",
+		`C:\services\api`,
+		"deploy",
+		`D:\logs`,
+	).WithTargetOS(supervisordkratos.TargetWindows)
+
+	content, err := supervisordkratos.BuildProgramConfig(program)
+	require.NoError(t, err)
+	t.Log(content)
+
+	const expected = `[program:api]
+user            = deploy
+directory       = C:\services\api
+command         = C:\services\api\bin\api
+stdout_logfile  = D:\logs\api.log
+stderr_logfile  = D:\logs\api.err
+`
+
+	require.Equal(t, expected, content)
+}
@@ -0,0 +1,123 @@
+package supervisordkratos
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/yyle88/must"
+)
+
+// tenantSep joins tenant name and program name, e.g. acme.api
+// tenantSep 连接租户名称和程序名称，例如 acme.api
+const tenantSep = "."
+
+// Tenant owner of programs on a shared host
+// Programs get name prefix, live under BaseDir and run as UserName
+//
+// Tenant 共享主机上程序的所有者
+// 程序带有名称前缀，位于 BaseDir 下，并以 UserName 运行
+type Tenant struct {
+	Name     string           // Tenant name, used as program name prefix // 租户名称，用作程序名称前缀
+	BaseDir  string           // Directory holding every tenant path // 包含所有租户路径的目录
+	UserName string           // Account every tenant program runs as // 所有租户程序使用的运行账户
+	Programs []*ProgramConfig // Programs created via NewProgramConfig // 通过 NewProgramConfig 创建的程序
+}
+
+// NewTenant create new Tenant with base directory and run user
+// 创建包含基础目录和运行账户的新 Tenant
+func NewTenant(name string, baseDir string, userName string) *Tenant {
+	must.Nice(name)
+	must.False(strings.Contains(name, tenantSep))
+	must.True(TargetLinux.IsAbs(baseDir))
+	return &Tenant{
+		Name:     name,
+		BaseDir:  TargetLinux.Clean(baseDir),
+		UserName: must.Nice(userName),
+		Programs: make([]*ProgramConfig, 0),
+	}
+}
+
+// NewProgramConfig create program named tenant.name with root and slogRoot relative to BaseDir
+// Prefix goes into section and log file names only, the binary stays at bin/name
+// Paths escaping BaseDir through ".." panic
+//
+// NewProgramConfig 创建名为 tenant.name 的程序，root 和 slogRoot 相对于 BaseDir
+// 前缀只用于段落名称和日志文件名，二进制仍位于 bin/name
+// 通过 ".." 逃出 BaseDir 的路径会触发 panic
+func (t *Tenant) NewProgramConfig(name string, root string, slogRoot string) *ProgramConfig {
+	must.Nice(name)
+	must.False(TargetLinux.IsAbs(root))
+	must.False(TargetLinux.IsAbs(slogRoot))
+	program := NewProgramConfig(t.Name+tenantSep+name, root, t.UserName, slogRoot).
+		WithBinaryName(name).
+		WithBaseDir(t.BaseDir)
+	must.True(t.Contains(program.Root))
+	must.True(t.Contains(program.SlogRoot))
+	t.Programs = append(t.Programs, program)
+	return program
+}
+
+// Contains checks if path sits inside tenant BaseDir
+// Contains 检查路径是否位于租户 BaseDir 内
+func (t *Tenant) Contains(p string) bool {
+	cleaned := TargetLinux.Clean(p)
+	return cleaned == t.BaseDir || strings.HasPrefix(cleaned, t.BaseDir+"/")
+}
+
+// Group returns group named by tenant holding every tenant program
+// Group 返回以租户命名、包含所有租户程序的组
+func (t *Tenant) Group() *GroupConfig {
+	group := NewGroupConfig(t.Name)
+	for _, program := range t.Programs {
+		group.AddProgram(program)
+	}
+	return group
+}
+
+// Validate checks tenant programs still keep prefix, paths and run user after later edits
+// Run users are checked as Group renders them, group user settings applied
+//
+// Validate 检查租户程序在后续修改后仍保持前缀、路径和运行账户
+// 运行账户按 Group 渲染时的结果检查，已应用组的账户设置
+func (t *Tenant) Validate() error {
+	group := t.Group()
+	for _, program := range group.resolvedPrograms(group.Programs) {
+		if !strings.HasPrefix(program.Name, t.Name+tenantSep) {
+			return errors.Errorf("tenant %s: program %s lacks prefix %s%s", t.Name, program.Name, t.Name, tenantSep)
+		}
+		if !t.Contains(program.Root) {
+			return errors.Errorf("tenant %s: program %s root %q is outside %s", t.Name, program.Name, program.Root, t.BaseDir)
+		}
+		if !program.AutoLogs.Get() && !t.Contains(program.SlogRoot) {
+			return errors.Errorf("tenant %s: program %s slog root %q is outside %s", t.Name, program.Name, program.SlogRoot, t.BaseDir)
+		}
+		if program.UserName != t.UserName {
+			return errors.Errorf("tenant %s: program %s runs as %q, tenant user is %q", t.Name, program.Name, program.UserName, t.UserName)
+		}
+	}
+	return nil
+}
+
+// ValidateTenants checks each tenant and collisions between tenants sharing one host
+// Names, base directories and run users must not overlap
+//
+// ValidateTenants 检查每个租户以及共享同一主机的租户之间的冲突
+// 名称、基础目录和运行账户不得重叠
+func ValidateTenants(tenants ...*Tenant) error {
+	for idx, tenant := range tenants {
+		if err := tenant.Validate(); err != nil {
+			return err
+		}
+		for _, other := range tenants[:idx] {
+			switch {
+			case tenant.Name == other.Name:
+				return errors.Errorf("tenant %s: name is used twice", tenant.Name)
+			case tenant.Contains(other.BaseDir) || other.Contains(tenant.BaseDir):
+				return errors.Errorf("tenant %s: base dir %s overlaps tenant %s base dir %s", tenant.Name, tenant.BaseDir, other.Name, other.BaseDir)
+			case tenant.UserName == other.UserName:
+				return errors.Errorf("tenant %s: user %q is shared with tenant %s", tenant.Name, tenant.UserName, other.Name)
+			}
+		}
+	}
+	return nil
+}
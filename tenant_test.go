@@ -0,0 +1,63 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantGroup(t *testing.T) {
+	// Test tenant programs get prefix, paths under base dir and tenant user
+	// 测试租户程序带有前缀、位于基础目录下并使用租户账户
+	acme := supervisordkratos.NewTenant("acme", "/srv/tenants/acme", "acme")
+	acme.NewProgramConfig("api", "apps/api", "logs")
+
+	content, err := supervisordkratos.BuildGroupConfig(acme.Group())
+	require.NoError(t, err)
+	t.Log(content)
+
+	const expected = `[group:acme]
+programs=acme.api
+
+
+[program:acme.api]
+user            = acme
+directory       = /srv/tenants/acme/apps/api
+command         = /srv/tenants/acme/apps/api/bin/api
+stdout_logfile  = /srv/tenants/acme/logs/acme.api.log
+stderr_logfile  = /srv/tenants/acme/logs/acme.api.err
+`
+
+	require.Equal(t, expected, content)
+	require.NoError(t, acme.Validate())
+
+	// Parsed back, the binary keeps its own name
+	// 解析回来后，二进制保留自己的名称
+	programs, err := supervisordkratos.ParseProgramConfigs(content, supervisordkratos.ParseStrict)
+	require.NoError(t, err)
+	require.Equal(t, "api", programs[0].BinaryName)
+
+	require.Panics(t, func() {
+		acme.NewProgramConfig("escape", "../globex/api", "logs")
+	})
+}
+
+func TestValidateTenants(t *testing.T) {
+	// Test tenants sharing a host cannot collide or drift outside their base
+	// 测试共享主机的租户不能冲突或偏离其基础目录
+	acme := supervisordkratos.NewTenant("acme", "/srv/tenants/acme", "acme")
+	acme.NewProgramConfig("api", "apps/api", "logs")
+	globex := supervisordkratos.NewTenant("globex", "/srv/tenants/globex", "globex")
+	globex.NewProgramConfig("api", "apps/api", "logs")
+	require.NoError(t, supervisordkratos.ValidateTenants(acme, globex))
+
+	nested := supervisordkratos.NewTenant("nested", "/srv/tenants/acme/nested", "nested")
+	require.ErrorContains(t, supervisordkratos.ValidateTenants(acme, nested), "overlaps")
+
+	shared := supervisordkratos.NewTenant("shared", "/srv/tenants/shared", "acme")
+	require.ErrorContains(t, supervisordkratos.ValidateTenants(acme, shared), "shared with")
+
+	globex.Programs[0].UserName = "root"
+	require.ErrorContains(t, supervisordkratos.ValidateTenants(acme, globex), "tenant user")
+}
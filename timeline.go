@@ -0,0 +1,78 @@
+package supervisordkratos
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/yyle88/must"
+	"github.com/yyle88/printgo"
+)
+
+// TimelineStep one state change supervisord goes through
+// TimelineStep supervisord 经历的一次状态变化
+type TimelineStep struct {
+	At     time.Duration // Offset from first start // 相对首次启动的偏移
+	State  ProcessState  // State entered // 进入的状态
+	Detail string        // What happens // 发生的事情
+}
+
+// BackoffTimeline states supervisord follows when every start of program crashes
+// BackoffTimeline 程序每次启动都崩溃时 supervisord 经历的状态
+type BackoffTimeline struct {
+	Program string          // Program name // 程序名称
+	Steps   []*TimelineStep // Ordered state changes // 有序的状态变化
+	Fatal   bool            // Ends in FATAL, false when restarts go on forever // 以 FATAL 结束，无限重启时为 false
+}
+
+// SimulateBackoff replay timeline of program crashing crashAfter into each start
+// Crashes before startsecs go through BACKOFF with 1s, 2s ... delays and end in FATAL
+// Crashes after startsecs count as EXITED and autorestart decides, retry count resets
+//
+// SimulateBackoff 重放程序每次启动后 crashAfter 崩溃的时间线
+// 在 startsecs 之前崩溃会经历延迟 1s、2s ... 的 BACKOFF 并以 FATAL 结束
+// 在 startsecs 之后崩溃算作 EXITED，由 autorestart 决定，重试计数重置
+func SimulateBackoff(program *ProgramConfig, crashAfter time.Duration) *BackoffTimeline {
+	must.Full(program)
+	must.True(crashAfter >= 0)
+
+	timeline := &BackoffTimeline{Program: program.Name, Steps: make([]*TimelineStep, 0)}
+	startSecs := time.Duration(program.StartSecs.Get()) * time.Second
+	if crashAfter >= startSecs {
+		autoRestart := formatAutoRestart(program.AutoRestart.Get())
+		timeline.Steps = append(timeline.Steps,
+			&TimelineStep{At: 0, State: ProcessStarting, Detail: "attempt 1"},
+			&TimelineStep{At: startSecs, State: ProcessRunning, Detail: "stayed up startsecs=" + strconv.Itoa(program.StartSecs.Get())},
+			&TimelineStep{At: crashAfter, State: ProcessExited, Detail: "crashed after running"},
+		)
+		if autoRestart != "false" {
+			timeline.Steps = append(timeline.Steps, &TimelineStep{At: crashAfter, State: ProcessStarting, Detail: "autorestart=" + autoRestart + " restarts at once, retry count resets, never FATAL"})
+		}
+		return timeline
+	}
+
+	var at time.Duration
+	startRetries := program.StartRetries.Get()
+	for attempt := 1; attempt <= startRetries+1; attempt++ {
+		timeline.Steps = append(timeline.Steps, &TimelineStep{At: at, State: ProcessStarting, Detail: "attempt " + strconv.Itoa(attempt)})
+		at += crashAfter
+		if attempt > startRetries {
+			timeline.Steps = append(timeline.Steps, &TimelineStep{At: at, State: ProcessFatal, Detail: "gave up after " + strconv.Itoa(attempt) + " attempts"})
+			break
+		}
+		delay := time.Duration(attempt) * time.Second
+		timeline.Steps = append(timeline.Steps, &TimelineStep{At: at, State: ProcessBackoff, Detail: "exited too quickly, retry in " + delay.String()})
+		at += delay
+	}
+	timeline.Fatal = true
+	return timeline
+}
+
+// String render timeline as aligned lines, one state change per line
+// String 将时间线渲染为对齐的行，每行一次状态变化
+func (t *BackoffTimeline) String() string {
+	ptx := printgo.NewPTX()
+	for _, step := range t.Steps {
+		ptx.Printf("%8s  %-8s  %s\n", step.At, step.State, step.Detail)
+	}
+	return ptx.String()
+}
@@ -0,0 +1,65 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateBackoffFatal(t *testing.T) {
+	// Test default settings with crash before startsecs end in FATAL
+	// 测试默认设置下在 startsecs 之前崩溃最终进入 FATAL
+	program := supervisordkratos.NewProgramConfig(
+		"api",
+		"/opt/api",
+		"deploy",
+		"/var/log/api",
+	).WithStartSecs(5)
+
+	timeline := supervisordkratos.SimulateBackoff(program, 2*time.Second)
+	content := timeline.String()
+	t.Log(content)
+
+	const expected = `      0s  STARTING  attempt 1
+      2s  BACKOFF   exited too quickly, retry in 1s
+      3s  STARTING  attempt 2
+      5s  BACKOFF   exited too quickly, retry in 2s
+      7s  STARTING  attempt 3
+      9s  BACKOFF   exited too quickly, retry in 3s
+     12s  STARTING  attempt 4
+     14s  FATAL     gave up after 4 attempts
+`
+
+	require.Equal(t, expected, content)
+	require.True(t, timeline.Fatal)
+	require.Equal(t, supervisordkratos.TimeToFatal(3, 2*time.Second), timeline.Steps[len(timeline.Steps)-1].At)
+}
+
+func TestSimulateBackoffRunning(t *testing.T) {
+	// Test crash after startsecs never reaches FATAL
+	// 测试在 startsecs 之后崩溃永远不会进入 FATAL
+	program := supervisordkratos.NewProgramConfig(
+		"api",
+		"/opt/api",
+		"deploy",
+		"/var/log/api",
+	)
+
+	timeline := supervisordkratos.SimulateBackoff(program, 30*time.Second)
+	content := timeline.String()
+	t.Log(content)
+
+	const expected = `      0s  STARTING  attempt 1
+      1s  RUNNING   stayed up startsecs=1
+     30s  EXITED    crashed after running
+     30s  STARTING  autorestart=unexpected restarts at once, retry count resets, never FATAL
+`
+
+	require.Equal(t, expected, content)
+	require.False(t, timeline.Fatal)
+
+	stopped := supervisordkratos.SimulateBackoff(program.WithAutoRestart(false), 30*time.Second)
+	require.Equal(t, supervisordkratos.ProcessExited, stopped.Steps[len(stopped.Steps)-1].State)
+}
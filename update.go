@@ -0,0 +1,184 @@
+package supervisordkratos
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/yyle88/must"
+)
+
+// UpdateProgramInFile replace [program:name] section in existing config file, other bytes stay untouched
+// Comments above the old header are replaced too, keep them in program Comments to retain them
+// Missing section is appended, and added to programs= of the single group in the file
+// File is replaced through rename, a crash never leaves it half written, symlinks are followed to their target
+//
+// UpdateProgramInFile 替换现有配置文件中的 [program:name] 段落，其余字节保持不变
+// 旧段头上方的注释也会被替换，如需保留请放入程序的 Comments
+// 段落不存在时追加到末尾，并加入文件中唯一组的 programs= 列表
+// 文件通过重命名替换，崩溃时不会留下写了一半的文件，符号链接会解析到其目标
+func UpdateProgramInFile(path string, program *ProgramConfig) error {
+	must.Full(program)
+
+	// Rename over a symlink would replace the link itself with a regular file
+	// 直接重命名覆盖符号链接会将链接本身替换为普通文件
+	path, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	text, err := replaceProgramSection(string(data), program)
+	if err != nil {
+		return errors.WithMessagef(err, "update %s", path)
+	}
+	return writeFileAtomic(path, []byte(text), info.Mode().Perm())
+}
+
+// writeFileAtomic write data to temp file in the same directory, then rename it over path
+// writeFileAtomic 将数据写入同一目录下的临时文件，然后重命名覆盖 path
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() { _ = os.Remove(temp.Name()) }()
+
+	if _, err := temp.Write(data); err != nil {
+		_ = temp.Close()
+		return errors.WithStack(err)
+	}
+	if err := temp.Chmod(perm); err != nil {
+		_ = temp.Close()
+		return errors.WithStack(err)
+	}
+	if err := temp.Sync(); err != nil {
+		_ = temp.Close()
+		return errors.WithStack(err)
+	}
+	if err := temp.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// replaceProgramSection splice rendered program section into config text
+// replaceProgramSection 将渲染的程序段落拼接进配置文本
+func replaceProgramSection(text string, program *ProgramConfig) (string, error) {
+	document, err := ParseDocument(text)
+	if err != nil {
+		return "", err
+	}
+	rendered := strings.TrimSuffix(NewProgramSection(program).String(), "\n")
+	lines := strings.Split(text, "\n")
+
+	if start, end, ok := sectionSpan(lines, "program:"+program.Name); ok {
+		lines = slices.Replace(lines, start, end, strings.Split(rendered, "\n")...)
+		return checkSpliced(strings.Join(lines, "\n"))
+	}
+
+	// New section joins the single group of the file
+	// 新段落加入文件中唯一的组
+	groups := make([]*Section, 0)
+	for _, section := range document.Sections {
+		if strings.HasPrefix(section.Name, "group:") {
+			groups = append(groups, section)
+		}
+	}
+	listed := slices.ContainsFunc(groups, func(section *Section) bool {
+		value, _ := section.Lookup("programs")
+		return slices.ContainsFunc(strings.Split(value, ","), func(item string) bool {
+			return strings.TrimSpace(item) == program.Name
+		})
+	})
+	if !listed && len(groups) > 1 {
+		return "", errors.Errorf("program %s: file has %d groups, cannot pick one", program.Name, len(groups))
+	}
+	if !listed && len(groups) == 1 {
+		start, end, _ := sectionSpan(lines, groups[0].Name)
+		idx := slices.IndexFunc(lines[start:end], func(line string) bool {
+			key, _, ok := strings.Cut(line, "=")
+			return ok && strings.TrimSpace(key) == "programs"
+		})
+		if idx < 0 {
+			return "", errors.Errorf("section %s: no programs entry", groups[0].Name)
+		}
+		last := start + idx
+		for last+1 < end && strings.TrimSpace(lines[last+1]) != "" && (lines[last+1][0] == ' ' || lines[last+1][0] == '\t') {
+			last++
+		}
+		// Name goes before an inline comment, not into it
+		// 名称放在行内注释之前，而不是注释之中
+		value, _ := cutInlineComment(lines[last])
+		value = strings.TrimRight(value, " \t")
+		rest := lines[last][len(value):]
+		if strings.TrimSpace(rest) == "" {
+			rest = ""
+		}
+		lines[last] = value + "," + program.Name + rest
+	}
+
+	result := strings.Join(lines, "\n")
+	if !strings.HasSuffix(result, "\n") {
+		result += "\n"
+	}
+	return checkSpliced(result + "\n" + rendered + "\n")
+}
+
+// sectionSpan line range [start, end) of section, leading comments included, trailing blanks and next comments excluded
+// sectionSpan 段落的行范围 [start, end)，包含前置注释，不包含尾部空行和下一段的注释
+func sectionSpan(lines []string, name string) (int, int, bool) {
+	header := slices.IndexFunc(lines, func(line string) bool {
+		return strings.TrimSpace(line) == "["+name+"]"
+	})
+	if header < 0 {
+		return 0, 0, false
+	}
+	start := header
+	for start > 0 && isCommentLine(lines[start-1]) {
+		start--
+	}
+	next := len(lines)
+	for idx := header + 1; idx < len(lines); idx++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[idx]), "[") {
+			next = idx
+			break
+		}
+	}
+	// Section ends at its last entry, comments between entries stay inside
+	// 段落在最后一个条目处结束，条目之间的注释保留在段落内
+	end := header + 1
+	for idx := header + 1; idx < next; idx++ {
+		if trimmed := strings.TrimSpace(lines[idx]); trimmed != "" && !isCommentLine(lines[idx]) {
+			end = idx + 1
+		}
+	}
+	return start, end, true
+}
+
+// isCommentLine checks if line is a ';' or '#' comment
+// isCommentLine 检查行是否为 ';' 或 '#' 注释
+func isCommentLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, ";") || strings.HasPrefix(trimmed, "#")
+}
+
+// checkSpliced makes sure spliced text still parses
+// checkSpliced 确保拼接后的文本仍可解析
+func checkSpliced(text string) (string, error) {
+	if _, err := ParseDocument(text); err != nil {
+		return "", errors.WithMessage(err, "spliced config does not parse")
+	}
+	return text, nil
+}
@@ -0,0 +1,160 @@
+package supervisordkratos_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateProgramInFile(t *testing.T) {
+	// Test only the target section changes, hand edits elsewhere stay byte-for-byte
+	// 测试只有目标段落变化，其他位置的手工编辑逐字节保留
+	const text = `; owned by platform team
+[group:cluster]
+programs = api,worker
+
+; api tuned by hand
+[program:api]
+command=/opt/api/bin/api
+user=deploy
+
+; keep worker quiet
+[program:worker]
+command   =   /opt/worker/bin/worker
+`
+	path := filepath.Join(t.TempDir(), "cluster.conf")
+	require.NoError(t, os.WriteFile(path, []byte(text), 0o640))
+
+	api := supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/api").
+		WithStartSecs(5)
+	require.NoError(t, supervisordkratos.UpdateProgramInFile(path, api))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	t.Log(string(data))
+
+	const expected = `; owned by platform team
+[group:cluster]
+programs = api,worker
+
+[program:api]
+user            = deploy
+directory       = /opt/api
+command         = /opt/api/bin/api
+startsecs       = 5
+stdout_logfile  = /var/log/api/api.log
+stderr_logfile  = /var/log/api/api.err
+
+; keep worker quiet
+[program:worker]
+command   =   /opt/worker/bin/worker
+`
+
+	require.Equal(t, expected, string(data))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+}
+
+func TestUpdateProgramInFileAppend(t *testing.T) {
+	// Test missing program is appended and listed in the group
+	// 测试缺失的程序被追加并列入组
+	const text = `[group:cluster]
+programs = api  
+
+[program:api]
+command=/opt/api/bin/api
+`
+	path := filepath.Join(t.TempDir(), "cluster.conf")
+	require.NoError(t, os.WriteFile(path, []byte(text), 0o644))
+
+	worker := supervisordkratos.NewProgramConfig("worker", "/opt/worker", "deploy", "/var/log/worker")
+	require.NoError(t, supervisordkratos.UpdateProgramInFile(path, worker))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	t.Log(string(data))
+
+	const expected = `[group:cluster]
+programs = api,worker
+
+[program:api]
+command=/opt/api/bin/api
+
+[program:worker]
+user            = deploy
+directory       = /opt/worker
+command         = /opt/worker/bin/worker
+stdout_logfile  = /var/log/worker/worker.log
+stderr_logfile  = /var/log/worker/worker.err
+`
+
+	require.Equal(t, expected, string(data))
+	require.NoError(t, supervisordkratos.Simulate(string(data)))
+
+	require.Error(t, supervisordkratos.UpdateProgramInFile(filepath.Join(t.TempDir(), "missing.conf"), worker))
+}
+
+func TestUpdateProgramInFileSpacedMembers(t *testing.T) {
+	// Test member listed with spaces after commas is not listed twice
+	// 测试逗号后带空格列出的成员不会被重复列出
+	const text = `[group:cluster]
+programs = api, worker
+
+[program:api]
+command=/opt/api/bin/api
+`
+	path := filepath.Join(t.TempDir(), "cluster.conf")
+	require.NoError(t, os.WriteFile(path, []byte(text), 0o640))
+
+	worker := supervisordkratos.NewProgramConfig("worker", "/opt/worker", "deploy", "/var/log/worker")
+	require.NoError(t, supervisordkratos.UpdateProgramInFile(path, worker))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	t.Log(string(data))
+	require.Contains(t, string(data), "programs = api, worker\n")
+	require.Contains(t, string(data), "[program:worker]\n")
+
+	// Rename keeps the mode and leaves no temp file behind
+	// 重命名保留权限且不留下临时文件
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestUpdateProgramInFileInlineComment(t *testing.T) {
+	// Test new member lands before inline comment, symlinked file stays a symlink
+	// 测试新成员位于行内注释之前，符号链接文件仍保持为符号链接
+	const text = `[group:cluster]
+programs = api ; worker joins later
+
+[program:api]
+command=/opt/api/bin/api
+`
+	root := t.TempDir()
+	target := filepath.Join(root, "cluster.conf")
+	require.NoError(t, os.WriteFile(target, []byte(text), 0o640))
+	link := filepath.Join(root, "cluster-link.conf")
+	require.NoError(t, os.Symlink(target, link))
+
+	worker := supervisordkratos.NewProgramConfig("worker", "/opt/worker", "deploy", "/var/log/worker")
+	require.NoError(t, supervisordkratos.UpdateProgramInFile(link, worker))
+
+	data, err := os.ReadFile(target)
+	require.NoError(t, err)
+	t.Log(string(data))
+	require.Contains(t, string(data), "programs = api,worker ; worker joins later\n")
+	require.NoError(t, supervisordkratos.Simulate(string(data)))
+
+	info, err := os.Lstat(link)
+	require.NoError(t, err)
+	require.NotZero(t, info.Mode()&os.ModeSymlink)
+}
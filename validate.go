@@ -0,0 +1,300 @@
+package supervisordkratos
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/yyle88/must"
+)
+
+// processNumSuffix is appended to ProcessName when auto-fixing multi-instance programs
+// 自动修复多实例程序时追加到 ProcessName 的后缀
+const processNumSuffix = "_%(process_num)02d"
+
+// KratosBootstrapSecs typical seconds a Kratos service needs to bootstrap and register
+// Suggested value for Validator.WithBootstrapSecs
+//
+// KratosBootstrapSecs Kratos 服务完成启动和注册通常需要的秒数
+// 建议用于 Validator.WithBootstrapSecs
+const KratosBootstrapSecs = 3
+
+// Validator checks configs against supervisord rules and optional policies
+// Zero-valued policies are disabled
+//
+// Validator 根据 supervisord 规则和可选策略检查配置
+// 零值策略表示不启用
+type Validator struct {
+	BootstrapSecs int      // Expected bootstrap seconds, startsecs below it is error // 预期启动秒数，startsecs 低于该值视为错误
+	AllowedUsers  []string // Accounts programs may run as, blank allows any // 程序允许使用的账户，为空表示不限制
+	UppercaseEnv  bool     // Environment keys must be uppercase // 环境变量名必须为大写
+	OldCoreutils  bool     // Target coreutils older than 8.31 (RHEL 8, busybox), lacks env --default-signal // 目标 coreutils 早于 8.31（RHEL 8、busybox），不支持 env --default-signal
+
+	MinLogRetention LogRetentionPreset // Minimum log retention, blank disables // 最低日志保留要求，为空表示不启用
+}
+
+// NewValidator create new Validator with supervisord rules only
+// 创建只包含 supervisord 规则的 Validator
+func NewValidator() *Validator {
+	return &Validator{
+		BootstrapSecs: 0,
+		AllowedUsers:  nil,
+		UppercaseEnv:  false,
+		OldCoreutils:  false,
+
+		MinLogRetention: "",
+	}
+}
+
+// WithBootstrapSecs set expected bootstrap seconds for startsecs check
+// 设置 startsecs 检查的预期启动秒数
+func (v *Validator) WithBootstrapSecs(bootstrapSecs int) *Validator {
+	v.BootstrapSecs = bootstrapSecs
+	return v
+}
+
+// WithAllowedUsers restrict accounts programs may run as
+// 限制程序允许使用的账户
+func (v *Validator) WithAllowedUsers(userNames ...string) *Validator {
+	v.AllowedUsers = userNames
+	return v
+}
+
+// WithOldCoreutils mark target coreutils as older than 8.31
+// WithOldCoreutils 标记目标 coreutils 早于 8.31
+func (v *Validator) WithOldCoreutils(oldCoreutils bool) *Validator {
+	v.OldCoreutils = oldCoreutils
+	return v
+}
+
+// ValidateProgram checks program config against rules
+// Returns error on settings that supervisord would refuse at startup
+//
+// ValidateProgram 根据规则检查程序配置
+// 当设置会被 supervisord 启动时拒绝时返回错误
+func (v *Validator) ValidateProgram(program *ProgramConfig) error {
+	must.Full(program)
+
+	// Relative paths depend on supervisord cwd, resolve them with WithBaseDir
+	// Paths rooted at %(ENV_NAME)s are left to SupervisordConfig.CheckEnvExpansions
+	// 相对路径依赖 supervisord 的工作目录，请使用 WithBaseDir 解析
+	// 以 %(ENV_NAME)s 开头的路径交由 SupervisordConfig.CheckEnvExpansions 检查
+	if !isEnvRooted(program.Root) && !program.TargetOS.Get().IsAbs(program.Root) {
+		return errors.Errorf("program %s: root %q is not absolute", program.Name, program.Root)
+	}
+	if !program.AutoLogs.Get() && !isEnvRooted(program.SlogRoot) && !program.TargetOS.Get().IsAbs(program.SlogRoot) {
+		return errors.Errorf("program %s: slog root %q is not absolute", program.Name, program.SlogRoot)
+	}
+	// supervisord requires process_num in process_name when numprocs > 1
+	// 当 numprocs > 1 时 supervisord 要求 process_name 包含 process_num
+	if program.NumProcs.Get() > 1 && !strings.Contains(program.ProcessName.Get(), "%(process_num)") {
+		return errors.Errorf("program %s: numprocs=%d but process_name %q lacks %%(process_num)", program.Name, program.NumProcs.Get(), program.ProcessName.Get())
+	}
+	// Instances share log paths unless they carry %(process_num), see WithInstanceLogs
+	// 日志路径不带 %(process_num) 时实例会共享该路径，参见 WithInstanceLogs
+	if program.NumProcs.Get() > 1 && !program.AutoLogs.Get() {
+		for _, path := range []string{program.stdoutLogfile(), program.stderrLogfile()} {
+			if !strings.Contains(path, "%(process_num)") {
+				return errors.Errorf("program %s: numprocs=%d instances share log file %s, use WithInstanceLogs", program.Name, program.NumProcs.Get(), path)
+			}
+		}
+	}
+	// supervisord refuses stopasgroup=true with killasgroup=false
+	// supervisord 拒绝 stopasgroup=true 与 killasgroup=false 同时出现
+	if program.StopAsGroup.Get() && program.KillAsGroup.IsSet() && !program.KillAsGroup.Get() {
+		return errors.Errorf("program %s: stopasgroup=true needs killasgroup=true", program.Name)
+	}
+	// unshare only creates namespaces, it cannot apply bind mounts
+	// unshare 只创建命名空间，无法应用绑定挂载
+	if program.Sandbox.IsSet() && program.Sandbox.Get().Tool == SandboxUnshare && len(program.Sandbox.Get().Binds) > 0 {
+		return errors.Errorf("program %s: unshare sandbox cannot apply bind mounts, use bwrap", program.Name)
+	}
+	// Schedule loop relies on /bin/sh
+	// 定时循环依赖 /bin/sh
+	if program.Schedule.IsSet() && program.TargetOS.Get() != TargetLinux {
+		return errors.Errorf("program %s: schedule needs /bin/sh, target %s has none", program.Name, program.TargetOS.Get())
+	}
+	// Loop shell does not forward stop signals, the running round is orphaned unless stopped as group
+	// 循环 shell 不会转发停止信号，除非按组停止，否则正在运行的一轮会成为孤儿进程
+	if program.Schedule.IsSet() && !program.StopAsGroup.Get() {
+		return errors.Errorf("program %s: schedule needs stopasgroup=true, otherwise stop orphans the running round", program.Name)
+	}
+	// Environment keys are rendered unquoted, invalid ones get mangled
+	// 环境变量名不带引号渲染，无效的名称会被破坏
+	if err := checkEnvKeys(program, v.UppercaseEnv); err != nil {
+		return err
+	}
+	// taskset is part of util-linux
+	// taskset 属于 util-linux
+	if program.CPUAffinity.Get() != "" && program.TargetOS.Get() != TargetLinux {
+		return errors.Errorf("program %s: cpu affinity needs taskset, target %s has none", program.Name, program.TargetOS.Get())
+	}
+	// Wrapper script relies on /bin/sh
+	// 包装脚本依赖 /bin/sh
+	if program.needsWrapper() && program.TargetOS.Get() != TargetLinux {
+		return errors.Errorf("program %s: wrapper script needs /bin/sh, target %s has none", program.Name, program.TargetOS.Get())
+	}
+	// SIGKILL after stopwaitsecs only hits the wrapper, the child keeps running unless killed as group
+	// stopwaitsecs 之后的 SIGKILL 只会杀死包装脚本，除非按组终止，否则子进程会继续运行
+	if program.needsWrapper() && !program.KillAsGroup.Get() {
+		return errors.Errorf("program %s: wrapper script needs killasgroup=true, otherwise SIGKILL orphans the binary", program.Name)
+	}
+	// Wrapper resets INT/QUIT for the binary with env --default-signal, only coreutils 8.31+ has it
+	// 包装脚本使用 env --default-signal 为二进制恢复 INT/QUIT，只有 coreutils 8.31+ 支持
+	if program.needsWrapper() && v.OldCoreutils && slices.Contains([]string{"INT", "QUIT"}, program.StopSignal.Get()) {
+		return errors.Errorf("program %s: wrapper forwarding stopsignal=%s needs coreutils 8.31+ env --default-signal", program.Name, program.StopSignal.Get())
+	}
+	// stopasgroup signals the binary directly, skipping the pre-stop hook
+	// stopasgroup 会直接向二进制发送信号，跳过停止前钩子
+	if program.PreStopHook.IsSet() && program.StopAsGroup.Get() {
+		return errors.Errorf("program %s: stopasgroup=true bypasses the pre-stop hook", program.Name)
+	}
+	// Instances would share one readiness marker
+	// 多个实例会共用同一个就绪标记文件
+	if program.Readiness.IsSet() && program.NumProcs.Get() > 1 {
+		return errors.Errorf("program %s: numprocs=%d instances would share readiness marker %s", program.Name, program.NumProcs.Get(), program.Readiness.Get().Marker)
+	}
+	// supervisord sends SIGKILL after stopwaitsecs, the hook must finish before
+	// supervisord 在 stopwaitsecs 之后发送 SIGKILL，钩子必须在此之前完成
+	if program.PreStopHook.IsSet() && time.Duration(program.StopWaitSecs.Get())*time.Second <= program.PreStopHook.Get().Timeout {
+		return errors.Errorf("program %s: stopwaitsecs=%d must exceed pre-stop hook timeout %s", program.Name, program.StopWaitSecs.Get(), program.PreStopHook.Get().Timeout)
+	}
+	// Security policy may pin service accounts per tier
+	// 安全策略可能为每个层级限定服务账户
+	if len(v.AllowedUsers) > 0 && !slices.Contains(v.AllowedUsers, program.UserName) {
+		return errors.Errorf("program %s: user %q is not in allowed users %v", program.Name, program.UserName, v.AllowedUsers)
+	}
+	// Production profiles keep enough logs for incident review
+	// 生产配置保留足够的日志用于事故复盘
+	if v.MinLogRetention != "" {
+		if err := checkLogRetention(program, v.MinLogRetention); err != nil {
+			return err
+		}
+	}
+	// startsecs shorter than bootstrap flaps between STARTING and BACKOFF
+	// startsecs 短于启动时间会在 STARTING 和 BACKOFF 之间反复
+	if v.BootstrapSecs > 0 && program.StartSecs.Get() < v.BootstrapSecs {
+		return errors.Errorf("program %s: startsecs=%d is below expected bootstrap %ds", program.Name, program.StartSecs.Get(), v.BootstrapSecs)
+	}
+	return nil
+}
+
+// ValidateGroup checks group config and each program in it
+// Returns the first error found
+//
+// ValidateGroup 检查组配置及其中每个程序
+// 返回发现的第一个错误
+func (v *Validator) ValidateGroup(group *GroupConfig) error {
+	must.Full(group)
+
+	// Check accounts as rendered, after group defaults and overrides
+	// 按渲染结果检查账户，即应用组默认值和覆盖之后
+	programs := group.resolvedPrograms(slices.Concat(group.Programs, group.Standalone))
+	for _, program := range programs {
+		if err := v.ValidateProgram(program); err != nil {
+			return errors.WithMessagef(err, "group %s", group.Name)
+		}
+		// Alias group would start the program a second time
+		// 别名组会再次启动该程序
+		if program.Alias.IsSet() {
+			return errors.Errorf("group %s: program %s has alias %s, aliased programs cannot join groups", group.Name, program.Name, program.Alias.Get())
+		}
+	}
+	if err := checkLogfileClash(programs); err != nil {
+		return errors.WithMessagef(err, "group %s", group.Name)
+	}
+	if err := checkDependencyOrder(programs); err != nil {
+		return errors.WithMessagef(err, "group %s", group.Name)
+	}
+	return nil
+}
+
+// ValidateProgramConfig checks program config against supervisord rules
+// Returns error on settings that supervisord would refuse at startup
+//
+// ValidateProgramConfig 根据 supervisord 规则检查程序配置
+// 当设置会被 supervisord 启动时拒绝时返回错误
+func ValidateProgramConfig(program *ProgramConfig) error {
+	return NewValidator().ValidateProgram(program)
+}
+
+// ValidateGroupConfig checks group config and each program in it
+// Returns the first error found
+//
+// ValidateGroupConfig 检查组配置及其中每个程序
+// 返回发现的第一个错误
+func ValidateGroupConfig(group *GroupConfig) error {
+	return NewValidator().ValidateGroup(group)
+}
+
+// checkLogfileClash reports two programs resolving to the same stdout/stderr path
+// Instances of one program are checked by ValidateProgram
+//
+// checkLogfileClash 报告解析到相同 stdout/stderr 路径的两个程序
+// 同一程序的多个实例由 ValidateProgram 检查
+func checkLogfileClash(programs []*ProgramConfig) error {
+	owners := make(map[string]string, len(programs)*2)
+	for _, program := range programs {
+		// AUTO logs get unique generated names
+		// AUTO 日志使用唯一的生成名称
+		if program.AutoLogs.Get() {
+			continue
+		}
+		for _, path := range []string{program.stdoutLogfile(), program.stderrLogfile()} {
+			if owner, exists := owners[path]; exists {
+				return errors.Errorf("program %s: log file %s clashes with program %s", program.Name, path, owner)
+			}
+			owners[path] = program.Name
+		}
+	}
+	return nil
+}
+
+// BuildProgramConfig validates then generates program configuration
+// Returns error instead of output when validation fails
+//
+// BuildProgramConfig 先校验再生成程序配置
+// 校验失败时返回错误而不是输出
+func BuildProgramConfig(program *ProgramConfig) (string, error) {
+	if err := ValidateProgramConfig(program); err != nil {
+		return "", err
+	}
+	return GenerateProgramConfig(program), nil
+}
+
+// BuildGroupConfig validates then generates group configuration
+// Returns error instead of output when validation fails
+//
+// BuildGroupConfig 先校验再生成组配置
+// 校验失败时返回错误而不是输出
+func BuildGroupConfig(group *GroupConfig) (string, error) {
+	if err := ValidateGroupConfig(group); err != nil {
+		return "", err
+	}
+	return GenerateGroupConfig(group), nil
+}
+
+// FixProcessName appends process_num to ProcessName when NumProcs > 1 needs it
+// Does nothing when the template already has process_num or runs single instance
+//
+// FixProcessName 当 NumProcs > 1 需要时向 ProcessName 追加 process_num
+// 模板已包含 process_num 或单实例运行时不做任何修改
+func (p *ProgramConfig) FixProcessName() *ProgramConfig {
+	if p.NumProcs.Get() > 1 && !strings.Contains(p.ProcessName.Get(), "%(process_num)") {
+		p.ProcessName.Set(p.ProcessName.Get() + processNumSuffix)
+	}
+	return p
+}
+
+// FixKillAsGroup sets KillAsGroup when StopAsGroup is on, matching supervisord semantics
+// Does nothing when StopAsGroup is off
+//
+// FixKillAsGroup 当 StopAsGroup 开启时设置 KillAsGroup，与 supervisord 语义一致
+// StopAsGroup 关闭时不做任何修改
+func (p *ProgramConfig) FixKillAsGroup() *ProgramConfig {
+	if p.StopAsGroup.Get() && !p.KillAsGroup.Get() {
+		p.KillAsGroup.Set(true)
+	}
+	return p
+}
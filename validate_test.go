@@ -0,0 +1,235 @@
+package supervisordkratos_test
+
+import (
+	"testing"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateProcessName(t *testing.T) {
+	// Test numprocs > 1 without process_num in process_name
+	// 测试 numprocs > 1 但 process_name 不含 process_num
+	program := supervisordkratos.NewProgramConfig(
+		"web-server",
+		"/opt/web-server",
+		"deploy",
+		"/var/log/cluster",
+	).WithNumProcs(3)
+
+	require.Error(t, supervisordkratos.ValidateProgramConfig(program))
+
+	_, err := supervisordkratos.BuildProgramConfig(program)
+	require.Error(t, err)
+
+	group := supervisordkratos.NewGroupConfig("cluster").AddProgram(program)
+	_, err = supervisordkratos.BuildGroupConfig(group)
+	require.Error(t, err)
+}
+
+func TestFixProcessName(t *testing.T) {
+	// Test auto-fix appends process_num suffix
+	// 测试自动修复追加 process_num 后缀
+	program := supervisordkratos.NewProgramConfig(
+		"web-server",
+		"/opt/web-server",
+		"deploy",
+		"/var/log/cluster",
+	).WithNumProcs(2).FixProcessName().WithInstanceLogs(true)
+
+	content, err := supervisordkratos.BuildProgramConfig(program)
+	require.NoError(t, err)
+	t.Log(content)
+
+	const expected = `[program:web-server]
+user            = deploy
+directory       = /opt/web-server
+command         = /opt/web-server/bin/web-server
+stdout_logfile  = /var/log/cluster/web-server_%(process_num)02d.log
+stderr_logfile  = /var/log/cluster/web-server_%(process_num)02d.err
+numprocs        = 2
+process_name    = %(program_name)s_%(process_num)02d
+`
+
+	require.Equal(t, expected, content)
+
+	// Fix again keeps template unchanged
+	// 再次修复保持模板不变
+	program.FixProcessName()
+	require.Equal(t, "%(program_name)s_%(process_num)02d", program.ProcessName.Get())
+}
+
+func TestValidateLogfileClash(t *testing.T) {
+	// Test two programs resolving to the same log files
+	// 测试两个程序解析到相同的日志文件
+	program1 := supervisordkratos.NewProgramConfig(
+		"worker",
+		"/opt/worker-a",
+		"deploy",
+		"/var/log/services",
+	)
+	program2 := supervisordkratos.NewProgramConfig(
+		"worker",
+		"/opt/worker-b",
+		"deploy",
+		"/var/log/services",
+	)
+
+	group := supervisordkratos.NewGroupConfig("workers").
+		AddProgram(program1).
+		AddProgram(program2)
+	require.Error(t, supervisordkratos.ValidateGroupConfig(group))
+
+	// Distinct log roots resolve to distinct files
+	// 不同的日志根目录解析到不同的文件
+	program2.SlogRoot = "/var/log/services-b"
+	require.NoError(t, supervisordkratos.ValidateGroupConfig(group))
+}
+
+func TestValidateInstanceLogfileClash(t *testing.T) {
+	// Test instances of one program writing to the same log files
+	// 测试同一程序的多个实例写入相同的日志文件
+	program := supervisordkratos.NewProgramConfig(
+		"worker",
+		"/opt/worker",
+		"deploy",
+		"/var/log/worker",
+	).WithNumProcs(3).FixProcessName()
+
+	require.ErrorContains(t, supervisordkratos.ValidateProgramConfig(program), "instances share log file")
+	group := supervisordkratos.NewGroupConfig("workers").AddProgram(program)
+	require.ErrorContains(t, supervisordkratos.ValidateGroupConfig(group), "instances share log file")
+
+	// process_num in file names gives each instance its own files
+	// 文件名中的 process_num 让每个实例使用自己的文件
+	program.WithInstanceLogs(true)
+	require.NoError(t, supervisordkratos.ValidateProgramConfig(program))
+	require.NoError(t, supervisordkratos.ValidateGroupConfig(group))
+
+	content := supervisordkratos.GenerateProgramConfig(program)
+	t.Log(content)
+	require.Contains(t, content, "stdout_logfile  = /var/log/worker/worker_%(process_num)02d.log\n")
+
+	programs, err := supervisordkratos.ParseProgramConfigs(content, supervisordkratos.ParseStrict)
+	require.NoError(t, err)
+	require.True(t, programs[0].InstanceLogs.Get())
+}
+
+func TestValidateBootstrapSecs(t *testing.T) {
+	// Test startsecs below expected Kratos bootstrap time
+	// 测试 startsecs 低于预期的 Kratos 启动时间
+	program := supervisordkratos.NewProgramConfig(
+		"user-service",
+		"/opt/user-service",
+		"deploy",
+		"/var/log/services",
+	)
+
+	// Disabled by default, supervisord accepts startsecs=1
+	// 默认不启用，supervisord 接受 startsecs=1
+	require.NoError(t, supervisordkratos.ValidateProgramConfig(program))
+
+	validator := supervisordkratos.NewValidator().WithBootstrapSecs(supervisordkratos.KratosBootstrapSecs)
+	require.Error(t, validator.ValidateProgram(program))
+
+	program.WithStartSecs(5)
+	require.NoError(t, validator.ValidateProgram(program))
+}
+
+func TestValidateRelativePaths(t *testing.T) {
+	// Test relative roots are rejected until resolved with base DIR
+	// 测试相对根目录在使用基础目录解析前被拒绝
+	program := supervisordkratos.NewProgramConfig(
+		"api",
+		"services/api/",
+		"deploy",
+		"logs/../log/api",
+	)
+	require.Error(t, supervisordkratos.ValidateProgramConfig(program))
+
+	content, err := supervisordkratos.BuildProgramConfig(program.WithBaseDir("/srv"))
+	require.NoError(t, err)
+	t.Log(content)
+
+	const expected = `[program:api]
+user            = deploy
+directory       = /srv/services/api
+command         = /srv/services/api/bin/api
+stdout_logfile  = /srv/log/api/api.log
+stderr_logfile  = /srv/log/api/api.err
+`
+
+	require.Equal(t, expected, content)
+}
+
+func TestGenerateCleansPaths(t *testing.T) {
+	// Test trailing slashes and dot segments are normalized
+	// 测试尾部斜杠和点段被规范化
+	program := supervisordkratos.NewProgramConfig(
+		"api",
+		"/opt/releases/../api/",
+		"deploy",
+		"/var/log/api/",
+	)
+
+	content := supervisordkratos.GenerateProgramConfig(program)
+	require.Contains(t, content, "directory       = /opt/api\n")
+	require.Contains(t, content, "command         = /opt/api/bin/api\n")
+	require.Contains(t, content, "stdout_logfile  = /var/log/api/api.log\n")
+}
+
+func TestValidateAllowedUsers(t *testing.T) {
+	// Test whitelist checks accounts after group defaults and overrides
+	// 测试白名单在应用组默认值和覆盖之后检查账户
+	group := supervisordkratos.NewGroupConfig("payments").
+		AddProgram(supervisordkratos.NewProgramConfig("api", "/opt/api", "root", "/var/log/api")).
+		AddProgram(supervisordkratos.NewProgramConfig("ledger", "/opt/ledger", "root", "/var/log/ledger")).
+		WithDefaultUser("svc-payments")
+
+	validator := supervisordkratos.NewValidator().WithAllowedUsers("svc-payments", "svc-ledger")
+	require.NoError(t, validator.ValidateGroup(group))
+
+	group.WithUserOverride("ledger", "svc-ledger")
+	require.NoError(t, validator.ValidateGroup(group))
+
+	group.WithUserOverride("api", "root")
+	err := validator.ValidateGroup(group)
+	require.Error(t, err)
+	t.Log(err)
+}
+
+func TestValidateStopAsGroup(t *testing.T) {
+	// Test stopasgroup with explicit killasgroup=false fails and auto-fix repairs it
+	// 测试 stopasgroup 与显式 killasgroup=false 校验失败，自动修复可修正
+	program := supervisordkratos.NewProgramConfig(
+		"api",
+		"/opt/api",
+		"deploy",
+		"/var/log/api",
+	).WithStopAsGroup(true).WithKillAsGroup(false)
+
+	_, err := supervisordkratos.BuildProgramConfig(program)
+	require.ErrorContains(t, err, "killasgroup")
+	require.Error(t, supervisordkratos.Simulate(supervisordkratos.GenerateProgramConfig(program)))
+
+	content, err := supervisordkratos.BuildProgramConfig(program.FixKillAsGroup())
+	require.NoError(t, err)
+	t.Log(content)
+	require.Contains(t, content, "killasgroup     = true\n")
+	require.NoError(t, supervisordkratos.Simulate(content))
+
+	// Unset killasgroup follows stopasgroup in effective options
+	// 未设置的 killasgroup 在有效选项中跟随 stopasgroup
+	implied := supervisordkratos.NewProgramConfig(
+		"worker",
+		"/opt/worker",
+		"deploy",
+		"/var/log/worker",
+	).WithStopAsGroup(true)
+	require.NoError(t, supervisordkratos.ValidateProgramConfig(implied))
+	for _, entry := range supervisordkratos.EffectiveOptions(implied) {
+		if entry.Key == "killasgroup" {
+			require.Equal(t, "true", entry.Value)
+		}
+	}
+}
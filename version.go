@@ -0,0 +1,49 @@
+package supervisordkratos
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/yyle88/must"
+)
+
+// ProbeBinaryVersion run the built binary with version flag and return first output line
+// Kratos services usually print ldflags-injected Version, pass the flag the binary accepts, e.g. "--version"
+//
+// ProbeBinaryVersion 使用版本参数运行已构建的二进制并返回输出的第一行
+// Kratos 服务通常打印通过 ldflags 注入的 Version，传入二进制接受的参数，例如 "--version"
+func ProbeBinaryVersion(program *ProgramConfig, flag string, timeout time.Duration) (string, error) {
+	must.Full(program)
+	must.Nice(flag)
+	must.True(timeout > 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	command := exec.CommandContext(ctx, program.commandPath(), flag)
+	command.Dir = program.Root
+	output, err := command.CombinedOutput()
+	if err != nil {
+		return "", errors.Wrapf(err, "program %s: %s %s", program.Name, program.commandPath(), flag)
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line, nil
+		}
+	}
+	return "", errors.Errorf("program %s: %s %s printed nothing", program.Name, program.commandPath(), flag)
+}
+
+// WithBinaryVersion record binary version, rendered as "binary: version=X" comment above the section
+// Ties the config artifact to the exact build, use ProbeBinaryVersion to read it from the binary
+//
+// WithBinaryVersion 记录二进制版本，以 "binary: version=X" 注释渲染在段落上方
+// 将配置产物与确切的构建关联，可使用 ProbeBinaryVersion 从二进制读取
+func (p *ProgramConfig) WithBinaryVersion(version string) *ProgramConfig {
+	must.Nice(version)
+	must.False(strings.Contains(version, "\n"))
+	p.BinaryVersion.Set(version)
+	return p
+}
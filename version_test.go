@@ -0,0 +1,39 @@
+package supervisordkratos_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeBinaryVersion(t *testing.T) {
+	// Test version printed by binary lands in section comment and effective options
+	// 测试二进制打印的版本写入段落注释和有效选项
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not installed")
+	}
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "bin"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "bin", "api"), []byte("#!/bin/sh\n[ \"$1\" = --version ] && echo 'v1.4.2-3f2a9c1' && exit 0\nexit 2\n"), 0o755))
+
+	program := supervisordkratos.NewProgramConfig("api", root, "deploy", "/var/log/api")
+	version, err := supervisordkratos.ProbeBinaryVersion(program, "--version", 5*time.Second)
+	require.NoError(t, err)
+	require.Equal(t, "v1.4.2-3f2a9c1", version)
+
+	_, err = supervisordkratos.ProbeBinaryVersion(program, "-v", 5*time.Second)
+	require.Error(t, err)
+
+	content := supervisordkratos.GenerateProgramConfig(program.WithBinaryVersion(version))
+	t.Log(content)
+	require.Contains(t, content, "; binary: version=v1.4.2-3f2a9c1\n[program:api]\n")
+
+	options := supervisordkratos.EffectiveOptions(program)
+	require.Equal(t, "binary.version", options[len(options)-1].Key)
+	require.Equal(t, supervisordkratos.ImpactCosmetic, supervisordkratos.ClassifyOption("binary.version"))
+}
@@ -0,0 +1,178 @@
+package supervisordkratos
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yyle88/must"
+	"github.com/yyle88/printgo"
+)
+
+// Readiness health probe creating a marker file once it passes, for file-based load balancer checks
+// Readiness 通过后创建标记文件的健康探针，供基于文件的负载均衡检查使用
+type Readiness struct {
+	Probe    string        // Shell command exiting 0 when service is ready // 服务就绪时以 0 退出的 shell 命令
+	Marker   string        // Marker file created when ready, removed on stop // 就绪时创建、停止时删除的标记文件
+	Interval time.Duration // Wait between probe attempts // 探测尝试之间的等待时间
+}
+
+// WithReadiness create marker file after probe succeeds, through a generated wrapper script
+// Marker is removed before start and on stop, so stale files never report a dead service ready
+// Also sets killasgroup, see wrapperNeedsKillAsGroup
+//
+// WithReadiness 通过生成的包装脚本，在探针成功后创建标记文件
+// 标记文件在启动前和停止时删除，因此过期文件不会将已停止的服务报告为就绪
+// 同时设置 killasgroup，参见 wrapperNeedsKillAsGroup
+func (p *ProgramConfig) WithReadiness(probe string, marker string, interval time.Duration) *ProgramConfig {
+	must.Nice(probe)
+	must.True(TargetLinux.IsAbs(marker))
+	must.True(interval >= time.Second)
+	p.Readiness.Set(&Readiness{Probe: probe, Marker: marker, Interval: interval})
+	return p.WithKillAsGroup(true)
+}
+
+// PreStopHook command run before the stop signal is forwarded to the binary
+// PreStopHook 在停止信号转发给二进制之前运行的命令
+type PreStopHook struct {
+	Command string        // Shell command, e.g. drain connections or deregister // shell 命令，例如排空连接或注销
+	Timeout time.Duration // Hook is killed after timeout, then the signal is forwarded // 超时后终止钩子，然后转发信号
+}
+
+// WithPreStopHook run command when supervisord stops the program, then forward the stop signal
+// stopwaitsecs must exceed timeout, otherwise supervisord kills the wrapper before the signal is forwarded
+// Also sets killasgroup, stopasgroup must stay off so the stop signal reaches the wrapper first
+//
+// WithPreStopHook 在 supervisord 停止程序时运行命令，然后转发停止信号
+// stopwaitsecs 必须大于 timeout，否则 supervisord 会在信号转发前杀死包装脚本
+// 同时设置 killasgroup，stopasgroup 必须保持关闭，使停止信号先到达包装脚本
+func (p *ProgramConfig) WithPreStopHook(command string, timeout time.Duration) *ProgramConfig {
+	must.Nice(command)
+	must.True(timeout >= time.Second)
+	p.PreStopHook.Set(&PreStopHook{Command: command, Timeout: timeout})
+	return p.WithKillAsGroup(true)
+}
+
+// WrapperScriptPath path the wrapper script is expected at, next to the binary
+// WrapperScriptPath 包装脚本的预期路径，与二进制文件同目录
+func WrapperScriptPath(program *ProgramConfig) string {
+	return program.TargetOS.Get().Join(program.Root, "bin", program.Name+"-wrapper.sh")
+}
+
+// GenerateWrapperScript generate /bin/sh wrapper running the binary as a child, handling readiness and pre-stop hook
+// On stop signal: marker removed, hook run under timeout, then the signal forwarded to the child
+// Install it executable at WrapperScriptPath, the [program:x] command points there
+//
+// GenerateWrapperScript 生成以子进程运行二进制的 /bin/sh 包装脚本，处理就绪状态和停止前钩子
+// 收到停止信号时：删除标记文件，在超时限制内运行钩子，然后将信号转发给子进程
+// 以可执行方式安装到 WrapperScriptPath，[program:x] 的 command 指向该位置
+func GenerateWrapperScript(program *ProgramConfig) string {
+	must.True(program.needsWrapper())
+
+	stopSignal := program.StopSignal.Get()
+	readiness := program.Readiness.Get()
+	preStopHook := program.PreStopHook.Get()
+
+	ptx := printgo.NewPTX()
+	ptx.Println("#!/bin/sh")
+	ptx.Println("# Wrapper generated by supervisordkratos for program " + program.Name)
+	if readiness != nil {
+		ptx.Println("marker=" + shellQuote(readiness.Marker))
+		ptx.Println(`rm -f "$marker"`)
+	}
+	// Trap goes in before the fork, a signal arriving before the child exists is replayed once it does
+	// 陷阱在派生子进程之前设置，子进程存在之前到达的信号会在其启动后重放
+	ptx.Println("child=")
+	ptx.Println("on_stop() {")
+	ptx.Println(`    if [ -z "$child" ]; then`)
+	ptx.Println("        stopping=1")
+	ptx.Println("        return")
+	ptx.Println("    fi")
+	if readiness != nil {
+		ptx.Println(`    rm -f "$marker"`)
+	}
+	if preStopHook != nil {
+		ptx.Println("    timeout " + strconv.Itoa(int(preStopHook.Timeout/time.Second)) + " /bin/sh -c " + shellQuote(preStopHook.Command))
+	}
+	ptx.Println(`    kill -` + stopSignal + ` "$child"`)
+	ptx.Println("}")
+	ptx.Println("trap on_stop " + stopSignal)
+	if stopSignal == "INT" || stopSignal == "QUIT" {
+		// dash starts background jobs with INT and QUIT ignored and `trap -` cannot undo that,
+		// env restores the defaults, --default-signal needs coreutils 8.31 or newer
+		// dash 启动后台任务时忽略 INT 和 QUIT，且 `trap -` 无法恢复，
+		// 使用 env 恢复默认处理，--default-signal 需要 coreutils 8.31 及以上版本
+		ptx.Println("env --default-signal=INT,QUIT " + program.wrappedCommand() + " &")
+	} else {
+		ptx.Println(program.wrappedCommand() + " &")
+	}
+	ptx.Println("child=$!")
+	ptx.Println(`[ -z "$stopping" ] || on_stop`)
+	if readiness != nil {
+		ptx.Println("(")
+		ptx.Println("    until " + readiness.Probe + "; do")
+		ptx.Println(`        kill -0 "$child" 2>/dev/null || exit 0`)
+		ptx.Println("        sleep " + strconv.Itoa(int(readiness.Interval/time.Second)))
+		ptx.Println("    done")
+		ptx.Println(`    touch "$marker"`)
+		ptx.Println(") &")
+	}
+	// wait returns early when the trap fires, keep waiting until child is gone
+	// 陷阱触发时 wait 会提前返回，持续等待直到子进程退出
+	ptx.Println(`wait "$child"`)
+	ptx.Println("status=$?")
+	ptx.Println(`while kill -0 "$child" 2>/dev/null; do`)
+	ptx.Println(`    wait "$child"`)
+	ptx.Println("    status=$?")
+	ptx.Println("done")
+	if readiness != nil {
+		ptx.Println(`rm -f "$marker"`)
+	}
+	ptx.Println("exit $status")
+	return ptx.String()
+}
+
+// needsWrapper checks if any option requires the generated wrapper script
+// needsWrapper 检查是否有选项需要生成的包装脚本
+func (p *ProgramConfig) needsWrapper() bool {
+	return p.Readiness.IsSet() || p.PreStopHook.IsSet()
+}
+
+// sandboxedCommand binary path with sandbox applied, as written into command=
+// sandboxedCommand 应用沙箱后的二进制路径，即写入 command= 的形式
+func (p *ProgramConfig) sandboxedCommand() string {
+	command := p.commandPath()
+	if p.Sandbox.IsSet() {
+		command = p.Sandbox.Get().Wrap(command)
+	}
+	return command
+}
+
+// wrappedCommand sandboxed command quoted word by word for /bin/sh
+// wrappedCommand 逐个单词引用的沙箱命令，供 /bin/sh 使用
+func (p *ProgramConfig) wrappedCommand() string {
+	args := []string{p.commandPath()}
+	if p.Sandbox.IsSet() {
+		args = p.Sandbox.Get().wrapArgs(p.commandPath())
+	}
+	words := make([]string, 0, len(args))
+	for _, arg := range args {
+		words = append(words, shellWord(arg))
+	}
+	return strings.Join(words, " ")
+}
+
+// shellWord keeps plain words as is, single-quotes the rest
+// shellWord 保留普通单词原样，其余使用单引号引用
+func shellWord(text string) string {
+	if text != "" && strings.Trim(text, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=:,+@%") == "" {
+		return text
+	}
+	return shellQuote(text)
+}
+
+// shellQuote single-quote text for /bin/sh
+// shellQuote 为 /bin/sh 使用单引号引用文本
+func shellQuote(text string) string {
+	return "'" + strings.ReplaceAll(text, "'", `'\''`) + "'"
+}
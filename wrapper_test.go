@@ -0,0 +1,252 @@
+package supervisordkratos_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/orzkratos/supervisordkratos"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateWrapperScriptReadiness(t *testing.T) {
+	// Test readiness wrapper script layout and command pointing to it
+	// 测试就绪包装脚本的布局以及指向它的命令
+	program := supervisordkratos.NewProgramConfig(
+		"api",
+		"/opt/api",
+		"deploy",
+		"/var/log/api",
+	).WithReadiness("curl -fsS http://127.0.0.1:8000/healthz >/dev/null", "/run/api/ready", 2*time.Second)
+
+	content := supervisordkratos.GenerateWrapperScript(program)
+	t.Log(content)
+
+	const expected = `#!/bin/sh
+# Wrapper generated by supervisordkratos for program api
+marker='/run/api/ready'
+rm -f "$marker"
+child=
+on_stop() {
+    if [ -z "$child" ]; then
+        stopping=1
+        return
+    fi
+    rm -f "$marker"
+    kill -TERM "$child"
+}
+trap on_stop TERM
+/opt/api/bin/api &
+child=$!
+[ -z "$stopping" ] || on_stop
+(
+    until curl -fsS http://127.0.0.1:8000/healthz >/dev/null; do
+        kill -0 "$child" 2>/dev/null || exit 0
+        sleep 2
+    done
+    touch "$marker"
+) &
+wait "$child"
+status=$?
+while kill -0 "$child" 2>/dev/null; do
+    wait "$child"
+    status=$?
+done
+rm -f "$marker"
+exit $status
+`
+
+	require.Equal(t, expected, content)
+
+	config, err := supervisordkratos.BuildProgramConfig(program)
+	require.NoError(t, err)
+	require.Contains(t, config, "command         = /opt/api/bin/api-wrapper.sh\n")
+	require.Equal(t, "/opt/api/bin/api-wrapper.sh", supervisordkratos.WrapperScriptPath(program))
+}
+
+func TestWrapperScriptRuns(t *testing.T) {
+	// Test wrapper creates marker once probe passes and removes it on stop
+	// 测试包装脚本在探针通过后创建标记文件，并在停止时删除
+	shell, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not installed")
+	}
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "bin"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "bin", "api"), []byte("#!/bin/sh\nexec sleep 30\n"), 0o755))
+	marker := filepath.Join(root, "ready")
+
+	program := supervisordkratos.NewProgramConfig("api", root, "deploy", root).
+		WithReadiness("true", marker, time.Second)
+	path := supervisordkratos.WrapperScriptPath(program)
+	require.NoError(t, os.WriteFile(path, []byte(supervisordkratos.GenerateWrapperScript(program)), 0o755))
+
+	command := exec.Command(shell, path)
+	require.NoError(t, command.Start())
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(marker)
+		return err == nil
+	}, 5*time.Second, 50*time.Millisecond)
+
+	require.NoError(t, command.Process.Signal(syscall.SIGTERM))
+	require.Error(t, command.Wait())
+	require.NoFileExists(t, marker)
+}
+
+func TestGenerateWrapperScriptPreStopHook(t *testing.T) {
+	// Test pre-stop hook runs under timeout before the stop signal is forwarded
+	// 测试停止前钩子在超时限制内运行，然后转发停止信号
+	program := supervisordkratos.NewProgramConfig(
+		"api",
+		"/opt/api",
+		"deploy",
+		"/var/log/api",
+	).WithStopSignal("INT").
+		WithPreStopHook("curl -fsS -X POST 'http://127.0.0.1:8000/drain'", 5*time.Second)
+
+	content := supervisordkratos.GenerateWrapperScript(program)
+	t.Log(content)
+
+	const expected = `#!/bin/sh
+# Wrapper generated by supervisordkratos for program api
+child=
+on_stop() {
+    if [ -z "$child" ]; then
+        stopping=1
+        return
+    fi
+    timeout 5 /bin/sh -c 'curl -fsS -X POST '\''http://127.0.0.1:8000/drain'\'''
+    kill -INT "$child"
+}
+trap on_stop INT
+env --default-signal=INT,QUIT /opt/api/bin/api &
+child=$!
+[ -z "$stopping" ] || on_stop
+wait "$child"
+status=$?
+while kill -0 "$child" 2>/dev/null; do
+    wait "$child"
+    status=$?
+done
+exit $status
+`
+
+	require.Equal(t, expected, content)
+
+	// Default stopwaitsecs=10 leaves room for a 5s hook, not for a 10s one
+	// 默认 stopwaitsecs=10 足够 5 秒钩子，但不够 10 秒钩子
+	require.NoError(t, supervisordkratos.ValidateProgramConfig(program))
+	program.WithPreStopHook("/opt/api/bin/deregister", 10*time.Second)
+	require.ErrorContains(t, supervisordkratos.ValidateProgramConfig(program), "pre-stop hook timeout")
+}
+
+func TestWrapperScriptRunsPreStopHook(t *testing.T) {
+	// Test hook output exists before the child is stopped
+	// 测试在子进程停止之前钩子已经执行
+	shell, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not installed")
+	}
+	if _, err := exec.LookPath("timeout"); err != nil {
+		t.Skip("timeout not installed")
+	}
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "bin"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "bin", "api"), []byte("#!/bin/sh\nexec sleep 30\n"), 0o755))
+	drained := filepath.Join(root, "drained")
+	marker := filepath.Join(root, "ready")
+
+	program := supervisordkratos.NewProgramConfig("api", root, "deploy", root).
+		WithReadiness("true", marker, time.Second).
+		WithPreStopHook("touch "+drained, time.Second)
+	path := supervisordkratos.WrapperScriptPath(program)
+	require.NoError(t, os.WriteFile(path, []byte(supervisordkratos.GenerateWrapperScript(program)), 0o755))
+
+	command := exec.Command(shell, path)
+	require.NoError(t, command.Start())
+	// Marker shows the trap is in place
+	// 标记文件表明陷阱已经设置
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(marker)
+		return err == nil
+	}, 5*time.Second, 50*time.Millisecond)
+
+	require.NoError(t, command.Process.Signal(syscall.SIGTERM))
+	require.Error(t, command.Wait())
+	require.FileExists(t, drained)
+	require.NoFileExists(t, marker)
+}
+
+func TestWrapperScriptForwardsINT(t *testing.T) {
+	// Test stopsignal=INT reaches a binary started in background by /bin/sh
+	// 测试 stopsignal=INT 能到达由 /bin/sh 在后台启动的二进制
+	shell, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not installed")
+	}
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "bin"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "bin", "api"), []byte("#!/bin/sh\nexec sleep 30\n"), 0o755))
+	marker := filepath.Join(root, "ready")
+
+	program := supervisordkratos.NewProgramConfig("api", root, "deploy", root).
+		WithStopSignal("INT").
+		WithReadiness("true", marker, time.Second)
+	path := supervisordkratos.WrapperScriptPath(program)
+	require.NoError(t, os.WriteFile(path, []byte(supervisordkratos.GenerateWrapperScript(program)), 0o755))
+
+	command := exec.Command(shell, path)
+	require.NoError(t, command.Start())
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(marker)
+		return err == nil
+	}, 5*time.Second, 50*time.Millisecond)
+
+	start := time.Now()
+	require.NoError(t, command.Process.Signal(syscall.SIGINT))
+	require.Error(t, command.Wait())
+	require.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestValidateWrapperGroupSignals(t *testing.T) {
+	// Test wrapper programs need killasgroup, hooks reject stopasgroup, readiness rejects numprocs
+	// 测试包装程序需要 killasgroup，钩子拒绝 stopasgroup，就绪探针拒绝多实例
+	newProgram := func() *supervisordkratos.ProgramConfig {
+		return supervisordkratos.NewProgramConfig("api", "/opt/api", "deploy", "/var/log/api")
+	}
+
+	program := newProgram().WithReadiness("true", "/run/api/ready", time.Second)
+	require.True(t, program.KillAsGroup.Get())
+	require.NoError(t, supervisordkratos.ValidateProgramConfig(program))
+	require.ErrorContains(t, supervisordkratos.ValidateProgramConfig(program.WithKillAsGroup(false)), "needs killasgroup=true")
+
+	program = newProgram().WithPreStopHook("true", time.Second).WithStopAsGroup(true)
+	require.ErrorContains(t, supervisordkratos.ValidateProgramConfig(program), "bypasses the pre-stop hook")
+
+	program = newProgram().WithReadiness("true", "/run/api/ready", time.Second).WithNumProcs(2).FixProcessName().WithInstanceLogs(true)
+	require.ErrorContains(t, supervisordkratos.ValidateProgramConfig(program), "share readiness marker")
+
+	program = newProgram().WithPreStopHook("true", time.Second).WithStopSignal("INT")
+	require.NoError(t, supervisordkratos.ValidateProgramConfig(program))
+	require.ErrorContains(t, supervisordkratos.NewValidator().WithOldCoreutils(true).ValidateProgram(program), "coreutils 8.31+")
+	require.NoError(t, supervisordkratos.NewValidator().WithOldCoreutils(true).ValidateProgram(program.WithStopSignal("TERM")))
+}
+
+func TestWrapperScriptQuotesCommand(t *testing.T) {
+	// Test wrapper quotes binary and sandbox paths holding shell metacharacters
+	// 测试包装脚本引用包含 shell 元字符的二进制和沙箱路径
+	program := supervisordkratos.NewProgramConfig(
+		"api",
+		"/opt/my api",
+		"deploy",
+		"/var/log/api",
+	).WithPreStopHook("true", time.Second).
+		WithSandbox(supervisordkratos.NewSandbox(supervisordkratos.SandboxBwrap).WithBind("/srv/$data", "/data"))
+
+	content := supervisordkratos.GenerateWrapperScript(program)
+	t.Log(content)
+	require.Contains(t, content, "\nbwrap --die-with-parent --unshare-all --share-net --proc /proc --dev /dev --bind '/srv/$data' /data -- '/opt/my api/bin/api' &\n")
+}